@@ -1,10 +1,13 @@
 package ai
 
 import (
+	"encoding/json"
 	"fmt"
 	"slices"
 
+	"github.com/ammar-ahmed22/chlog/git"
 	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
 )
 
 type GenerateChangelogEntryParams struct {
@@ -13,7 +16,18 @@ type GenerateChangelogEntryParams struct {
 	FromCommit string
 	ToCommit   string
 	Model      string
-	Tags       []string
+	Tags       []Tag
+	// PromptTemplate is a custom prompt template (see PromptData), the built-in Prompt is used if it is empty
+	PromptTemplate string
+	StyleGuide     string
+	Audience       Audience
+	// Language is the locale code of the language to write the changes in (e.g. de), the model's default is used if it is empty
+	Language string
+	// ReferencePatterns are used to list the issues referenced by each commit in the prompt
+	ReferencePatterns []git.ReferencePattern
+	Repository        RepositoryInfo
+	// ExcludeCommits are commits of the range that are left out of the prompt (e.g. commits already in the changelog)
+	ExcludeCommits []string
 }
 
 type GenerateChangelogEntryResponse struct {
@@ -24,24 +38,98 @@ type GenerateChangelogEntryResponse struct {
 
 type AIClient interface {
 	GenerateChangelogEntry(params GenerateChangelogEntryParams) (GenerateChangelogEntryResponse, error)
+	// GenerateChangelogEntryFromPrompt generates a changelog entry (using the entry schema) from a custom prompt.
+	// If tags are given, the tags of the changes are restricted to them.
+	GenerateChangelogEntryFromPrompt(model, prompt string, tags []string) (GenerateChangelogEntryResponse, error)
+	// GenerateMigration generates the upgrade guide of a change (using the migration schema) from a prompt built with
+	// BuildMigrationPrompt
+	GenerateMigration(model, prompt string) (GenerateMigrationResponse, error)
+	// GenerateSummary generates the summary and highlights of an entry (using the summary schema) from a prompt built
+	// with BuildSummaryPrompt
+	GenerateSummary(model, prompt string) (GenerateSummaryResponse, error)
+	// GenerateSecurityAdvisory generates the advisory information of a security change (using the security advisory
+	// schema) from a prompt built with BuildSecurityPrompt
+	GenerateSecurityAdvisory(model, prompt string) (GenerateSecurityAdvisoryResponse, error)
+	// GenerateTranslation generates the translation of the text of an entry (using the translation schema) from a prompt
+	// built with BuildTranslatePrompt
+	GenerateTranslation(model, prompt string) (GenerateTranslationResponse, error)
 }
 
-var DefaultTags = []string{"feature", "fix", "improvement", "deprecation", "security", "breaking", "documentation"}
-
+// Prompt is the built-in changelog generation prompt template (see PromptData for the available data)
 var Prompt = `
 You are a changelog generation assistant. Based on the provided Git commits and their diffs, generate a structured changelog entry that adheres exactly to the JSON schema.
 
 ## Rules:
 - Only use the information provided in the commit messages and diffs.
-- Each change should include a succint title, a detailed, end-user friendly description, and an impact statement.
+- Each change should include a succinct title, a detailed, end-user friendly description, and an impact statement.
 - Each change must be tagged appropriately. Valid tags are:
-  - %s
+{{.TagList}}
 - Each change must have at least one tag.
+- Each change must have an importance: major for changes most users notice, minor for smaller improvements and fixes, and trivial for changes users barely notice (e.g. refactors or typo fixes).
 - Each change should include the commit hash or hashes (if multiple) associated with it.
 - Each change must be associated with at least one commit.
 - You can have multiple changes associated with a single commit, up to your discretion.
 - Ordering of changes should be from most recent to oldest (most recent first, oldest last).
+- Output must be strictly valid JSON matching the schema. Do not include any explanation or extra text.` +
+	audiencePromptSection + languagePromptSection + referencesPromptSection + styleGuidePromptSection + `
+
+## Git Commits:
+Each commit is shown below with its hash, message, and code diff separated by "--- COMMIT ---".
+
+{{.History}}
+	`
+
+var ImportPrompt = `
+You are a changelog editing assistant. The changelog entry below was imported from a hand-written Markdown changelog, so some of its changes are terse and none of them have an impact statement. Complete the entry so it adheres exactly to the JSON schema.
+
+## Rules:
+- Only use the information provided in the changelog entry. Do not invent details.
+- Return exactly one change for each change in the entry, in the same order.
+- Keep the id, title, commits and tags of each change unchanged.
+- If a description is terse, expand it into a detailed, end-user friendly description. Otherwise keep it as is.
+- Each change must include an impact statement describing what and how the change affects the user or usage of the software.
+- Each change must have an importance: major for changes most users notice, minor for smaller improvements and fixes, and trivial for changes users barely notice (e.g. refactors or typo fixes).
+- Output must be strictly valid JSON matching the schema. Do not include any explanation or extra text.
+%s
+## Changelog Entry:
+%s
+	`
+
+var TranslatePrompt = `
+You are a changelog translation assistant. Translate the changelog entry below to %s so it adheres exactly to the JSON schema.
+
+## Rules:
+- Return exactly one change for each change in the entry, in the same order. Keep the id of each change unchanged.
+- Translate the summary of the entry, and the title, description, impact, migration summary, migration actions and security summary of each change.
+- Leave fields that are missing or empty in the entry empty (e.g. the migration of a change without one has an empty summary and no actions).
+- Keep code identifiers, commands, flags, file names and product names as they are.
+- Do not add, remove or change any information, and keep the tone of the original.
 - Output must be strictly valid JSON matching the schema. Do not include any explanation or extra text.
+%s
+## Changelog Entry:
+%s
+	`
+
+var RegeneratePrompt = `
+You are a changelog generation assistant. Part of a previously generated changelog entry has to be regenerated. Based on the provided Git commits and their diffs, the previous output and the user's feedback, generate an improved version that adheres exactly to the JSON schema.
+
+## Rules:
+- Only use the information provided in the commit messages, diffs and feedback.
+- Address the user's feedback. Keep anything in the previous output that the feedback does not ask to change.
+- %s
+- Each change should include a succinct title, a detailed, end-user friendly description, and an impact statement.
+- Each change must be tagged appropriately. Valid tags are:
+%s
+- Each change must have at least one tag.
+- Each change must have an importance: major for changes most users notice, minor for smaller improvements and fixes, and trivial for changes users barely notice (e.g. refactors or typo fixes).
+- Each change should include the commit hash or hashes (if multiple) associated with it.
+- Output must be strictly valid JSON matching the schema. Do not include any explanation or extra text.
+%s
+## Previous Output:
+%s
+
+## Feedback:
+%s
 
 ## Git Commits:
 Each commit is shown below with its hash, message, and code diff separated by "--- COMMIT ---".
@@ -49,6 +137,107 @@ Each commit is shown below with its hash, message, and code diff separated by "-
 %s
 	`
 
+type RegenerateParams struct {
+	Entry models.ChangelogEntry
+	// ChangeID is the ID of the change to regenerate. The whole entry is regenerated if it is empty.
+	ChangeID   string
+	Feedback   string
+	Tags       []Tag
+	StyleGuide string
+	// Language is the locale code of the language of the entry
+	Language string
+}
+
+// BuildRegeneratePrompt builds the prompt used to regenerate an entry (from its from and to references)
+// or a single change (from its commits) with the user's feedback
+func BuildRegeneratePrompt(params RegenerateParams) (string, error) {
+	var previous any = params.Entry
+	var commits []string
+	scope := "Generate the changes of the whole entry."
+	if params.ChangeID != "" {
+		change, found := lo.Find(params.Entry.Changes, func(change models.ChangelogChange) bool {
+			return change.ID == params.ChangeID
+		})
+		if !found {
+			return "", fmt.Errorf("change '%s' not found in version '%s'", params.ChangeID, params.Entry.Version)
+		}
+		previous = change
+		commits = change.Commits
+		scope = "Return exactly one change, which replaces the previous change."
+	} else {
+		var err error
+		commits, err = git.CommitRange(params.Entry.FromRef, params.Entry.ToRef)
+		if err != nil {
+			return "", fmt.Errorf("failed to get commits: %v", err)
+		}
+	}
+
+	historyWithDiff, err := git.CommitsHistoryWithDiff(commits)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit history with diff: %v", err)
+	}
+
+	previousJSON, err := json.MarshalIndent(previous, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal previous output: %v", err)
+	}
+
+	feedback := params.Feedback
+	if feedback == "" {
+		feedback = "No feedback, improve the previous output."
+	}
+	language := languageRule(params.Language, "the title, description and impact of each change", "commit hashes, tags and code identifiers")
+	return fmt.Sprintf(RegeneratePrompt, scope, FormatTags(params.Tags), language+styleGuideSection(params.StyleGuide), previousJSON, feedback, historyWithDiff), nil
+}
+
+// BuildImportPrompt builds the prompt used to fill the descriptions and impacts of an imported changelog entry
+func BuildImportPrompt(entry models.ChangelogEntry, styleGuide string) (string, error) {
+	entryJSON, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal changelog entry: %v", err)
+	}
+	return fmt.Sprintf(ImportPrompt, styleGuideSection(styleGuide), entryJSON), nil
+}
+
+type GenerateTranslationResponse struct {
+	Translation  models.ChangelogEntryTranslation
+	InputTokens  int
+	OutputTokens int
+}
+
+// TranslationSource returns the text of the entry that is translated: its summary, and the title, description, impact,
+// upgrade guide (without the before and after snippets) and security summary of its changes
+func TranslationSource(entry models.ChangelogEntry) models.ChangelogEntryTranslation {
+	return models.ChangelogEntryTranslation{
+		Summary: entry.Summary,
+		Changes: lo.Map(entry.Changes, func(change models.ChangelogChange, _ int) models.ChangelogTranslation {
+			translation := models.ChangelogTranslation{
+				ID:          change.ID,
+				Title:       change.Title,
+				Description: change.Description,
+				Impact:      change.Impact,
+			}
+			if change.Migration != nil {
+				translation.Migration = &models.MigrationTranslation{Summary: change.Migration.Summary, Actions: change.Migration.Actions}
+			}
+			if change.Security != nil {
+				translation.SecuritySummary = change.Security.Summary
+			}
+			return translation
+		}),
+	}
+}
+
+// BuildTranslatePrompt builds the prompt used to translate the text of an entry (see TranslationSource) to the language
+// (a locale code)
+func BuildTranslatePrompt(entry models.ChangelogEntry, language string, styleGuide string) (string, error) {
+	entryJSON, err := json.MarshalIndent(TranslationSource(entry), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal changelog entry: %v", err)
+	}
+	return fmt.Sprintf(TranslatePrompt, LanguageName(language), styleGuideSection(styleGuide), entryJSON), nil
+}
+
 func NewAIClient(provider, apiKey string) (AIClient, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required for provider: %s", provider)
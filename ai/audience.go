@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Audience is a reader of the changelog, its instructions are added to the prompt to adapt the wording of the changes
+type Audience struct {
+	Name         string
+	Instructions string
+}
+
+// InternalAudience is the audience of the team building the software. Its changes are not part of the public changelog.
+const InternalAudience = "internal"
+
+var DefaultAudiences = []Audience{
+	{
+		Name:         "end-user",
+		Instructions: "They use the software but do not read its code. Focus on visible behavior and benefits, avoid implementation details, code identifiers and jargon, and mark changes that are not visible to them (e.g. refactors, tests, CI) as internal.",
+	},
+	{
+		Name:         "developer",
+		Instructions: "They integrate with or contribute to the software. Be precise about behavior changes and mention the affected APIs, commands, flags, config keys and files.",
+	},
+	{
+		Name:         InternalAudience,
+		Instructions: "They are the team building the software. Include implementation details, refactors, tests and tooling changes, and mention the affected packages or files.",
+	},
+}
+
+// AudienceNames returns the names of the audiences
+func AudienceNames(audiences []Audience) []string {
+	return lo.Map(audiences, func(audience Audience, _ int) string {
+		return audience.Name
+	})
+}
+
+// FindAudience returns the audience with the name (case-insensitive)
+func FindAudience(audiences []Audience, name string) (Audience, bool) {
+	return lo.Find(audiences, func(audience Audience) bool {
+		return strings.EqualFold(audience.Name, strings.TrimSpace(name))
+	})
+}
@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"encoding/json"
+)
+
+// ModelPricing is the price in USD per 1M input and output tokens of each model, used for cost estimates
+var ModelPricing = map[string]struct {
+	Input  float64
+	Output float64
+}{
+	"gpt-4o-mini":      {Input: 0.15, Output: 0.60},
+	"gpt-4.1-mini":     {Input: 0.40, Output: 1.60},
+	"gemini-2.0-flash": {Input: 0.10, Output: 0.40},
+}
+
+// ResponseSchema returns the JSON schema of the changelog entry sent to the provider
+func ResponseSchema(provider string, tags []string) (any, error) {
+	switch provider {
+	case "gemini":
+		return GeminiSchema(changelogEntrySchema(tags))
+	default:
+		return changelogEntrySchema(tags), nil
+	}
+}
+
+// EstimateTokens roughly estimates the number of tokens of a text (about 4 characters per token)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+type DryRun struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Prompt   string `json:"prompt"`
+	Schema   any    `json:"schema"`
+	// EstimatedInputTokens is estimated from the prompt and schema, the output tokens are only known after generation
+	EstimatedInputTokens int     `json:"estimated_input_tokens"`
+	EstimatedInputCost   float64 `json:"estimated_input_cost_usd"`
+	OutputCostPerMillion float64 `json:"output_cost_per_million_tokens_usd"`
+	// FollowUpRequests are the requests that may be sent after the entry is generated, which are not part of the estimate
+	FollowUpRequests []string `json:"follow_up_requests,omitempty"`
+}
+
+// NewDryRun builds the request that would be sent to the provider without calling it
+func NewDryRun(provider string, params GenerateChangelogEntryParams) (*DryRun, error) {
+	prompt, err := BuildPrompt(params)
+	if err != nil {
+		return nil, err
+	}
+	return NewDryRunFromPrompt(provider, params.Model, prompt, TagNames(params.Tags))
+}
+
+func NewDryRunFromPrompt(provider, model, prompt string, tags []string) (*DryRun, error) {
+	schema, err := ResponseSchema(provider, tags)
+	if err != nil {
+		return nil, err
+	}
+	tokens := EstimateTokens(prompt)
+	if schemaJSON, err := json.Marshal(schema); err == nil {
+		tokens += EstimateTokens(string(schemaJSON))
+	}
+	pricing := ModelPricing[model]
+	return &DryRun{
+		Provider:             provider,
+		Model:                model,
+		Prompt:               prompt,
+		Schema:               schema,
+		EstimatedInputTokens: tokens,
+		EstimatedInputCost:   float64(tokens) * pricing.Input / 1_000_000,
+		OutputCostPerMillion: pricing.Output,
+	}, nil
+}
@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/ammar-ahmed22/chlog/git"
 	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/invopop/jsonschema"
 	"google.golang.org/genai"
 )
 
@@ -27,86 +27,39 @@ func NewGeminiAIClient(apiKey string) (*GeminiAIClient, error) {
 	return &GeminiAIClient{client: client}, nil
 }
 
+// geminiResponseConfig returns the config of a JSON response with the schema converted to a Gemini schema
+func geminiResponseConfig(schema *jsonschema.Schema) (*genai.GenerateContentConfig, error) {
+	responseSchema, err := GeminiSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the response schema: %v", err)
+	}
+	return &genai.GenerateContentConfig{
+		ResponseMIMEType: "application/json",
+		ResponseSchema:   responseSchema,
+	}, nil
+}
+
 // Compile-time check to ensure GeminiAIClient implements AIClient interface
 var _ AIClient = (*GeminiAIClient)(nil)
 
 func (c *GeminiAIClient) GenerateChangelogEntry(params GenerateChangelogEntryParams) (GenerateChangelogEntryResponse, error) {
-	config := &genai.GenerateContentConfig{
-		ResponseMIMEType: "application/json",
-		ResponseSchema: &genai.Schema{
-			Type: genai.TypeObject,
-			Properties: map[string]*genai.Schema{
-				"version": {
-					Type:        genai.TypeString,
-					Description: "The version number of the release. Leave as empty string.",
-				},
-				"date": {
-					Type:        genai.TypeString,
-					Description: "The date of the release. Leave as empty string.",
-				},
-				"from_ref": {
-					Type:        genai.TypeString,
-					Description: "The starting commit reference for the changelog entry. Leave as empty string.",
-				},
-				"to_ref": {
-					Type:        genai.TypeString,
-					Description: "The ending commit reference for the changelog entry. Leave as empty string.",
-				},
-				"changes": {
-					Type: genai.TypeArray,
-					Items: &genai.Schema{
-						Type: genai.TypeObject,
-						Properties: map[string]*genai.Schema{
-							"id": {
-								Type:        genai.TypeString,
-								Description: "The unique identifier of the change. Leave as empty string.",
-							},
-							"title": {
-								Type:        genai.TypeString,
-								Description: "The title of the change. Should be succinct.",
-							},
-							"description": {
-								Type:        genai.TypeString,
-								Description: "End-user friendly description of the change. Should be more verbose.",
-							},
-							"impact": {
-								Type:        genai.TypeString,
-								Description: "The impact of the change. Describe what and how the change affects the user or usage of the software.",
-							},
-							"commits": {
-								Type:        genai.TypeArray,
-								Description: "List of commit hashes associated with this change. Must have at least one value.",
-								Items: &genai.Schema{
-									Type: genai.TypeString,
-								},
-							},
-							"tags": {
-								Type: genai.TypeArray,
-								Items: &genai.Schema{
-									Type:        genai.TypeString,
-									Description: "Tags associated with this change",
-								},
-							},
-						},
-						Required:         []string{"title", "description", "impact", "commits", "tags"},
-						PropertyOrdering: []string{"id", "title", "description", "impact", "commits", "tags"},
-					},
-				},
-			},
-			PropertyOrdering: []string{"version", "date", "from_ref", "to_ref", "changes"},
-			Required:         []string{"changes"},
-		},
+	prompt, err := BuildPrompt(params)
+	if err != nil {
+		return GenerateChangelogEntryResponse{}, err
 	}
+	return c.GenerateChangelogEntryFromPrompt(params.Model, prompt, TagNames(params.Tags))
+}
 
-	historyWithDiff, err := git.CommitHistoryWithDiff(params.FromCommit, params.ToCommit)
+func (c *GeminiAIClient) GenerateChangelogEntryFromPrompt(model, prompt string, tags []string) (GenerateChangelogEntryResponse, error) {
+	config, err := geminiResponseConfig(changelogEntrySchema(tags))
 	if err != nil {
-		return GenerateChangelogEntryResponse{}, fmt.Errorf("failed to get commit history with diff: %w", err)
+		return GenerateChangelogEntryResponse{}, err
 	}
 
 	result, err := c.client.Models.GenerateContent(
 		context.Background(),
-		params.Model,
-		genai.Text(fmt.Sprintf(Prompt, params.Tags, historyWithDiff)),
+		model,
+		genai.Text(prompt),
 		config,
 	)
 	if err != nil {
@@ -118,9 +71,59 @@ func (c *GeminiAIClient) GenerateChangelogEntry(params GenerateChangelogEntryPar
 	if err := json.Unmarshal([]byte(resp), &changelogEntry); err != nil {
 		return GenerateChangelogEntryResponse{}, fmt.Errorf("Invalid JSON response from Gemini. Please try again.\nGenerated response: %s", resp)
 	}
+
+	if err := repairTags(&changelogEntry, tags); err != nil {
+		return GenerateChangelogEntryResponse{}, fmt.Errorf("Invalid tags in response from Gemini: %v. Please try again.", err)
+	}
 	return GenerateChangelogEntryResponse{
 		Entry:        changelogEntry,
 		InputTokens:  int(result.UsageMetadata.PromptTokenCount),
 		OutputTokens: int(result.UsageMetadata.CandidatesTokenCount),
 	}, nil
 }
+
+func (c *GeminiAIClient) GenerateMigration(model, prompt string) (GenerateMigrationResponse, error) {
+	migration, inputTokens, outputTokens, err := generateGeminiStructured[models.Migration](c, model, prompt, "migration", models.MigrationSchema)
+	return GenerateMigrationResponse{Migration: migration, InputTokens: inputTokens, OutputTokens: outputTokens}, err
+}
+
+func (c *GeminiAIClient) GenerateSummary(model, prompt string) (GenerateSummaryResponse, error) {
+	summary, inputTokens, outputTokens, err := generateGeminiStructured[models.ChangelogSummary](c, model, prompt, "summary", models.SummarySchema)
+	return GenerateSummaryResponse{Summary: summary, InputTokens: inputTokens, OutputTokens: outputTokens}, err
+}
+
+func (c *GeminiAIClient) GenerateSecurityAdvisory(model, prompt string) (GenerateSecurityAdvisoryResponse, error) {
+	advisory, inputTokens, outputTokens, err := generateGeminiStructured[models.SecurityAdvisory](c, model, prompt, "security advisory", models.SecurityAdvisorySchema)
+	return GenerateSecurityAdvisoryResponse{Advisory: advisory, InputTokens: inputTokens, OutputTokens: outputTokens}, err
+}
+
+func (c *GeminiAIClient) GenerateTranslation(model, prompt string) (GenerateTranslationResponse, error) {
+	translation, inputTokens, outputTokens, err := generateGeminiStructured[models.ChangelogEntryTranslation](c, model, prompt, "translation", models.TranslationSchema)
+	return GenerateTranslationResponse{Translation: translation, InputTokens: inputTokens, OutputTokens: outputTokens}, err
+}
+
+// generateGeminiStructured generates a response of type T that adheres to the schema from a prompt. It returns the
+// response with the number of input and output tokens used.
+func generateGeminiStructured[T any](c *GeminiAIClient, model, prompt, name string, schema *jsonschema.Schema) (T, int, int, error) {
+	var result T
+	config, err := geminiResponseConfig(schema)
+	if err != nil {
+		return result, 0, 0, err
+	}
+
+	response, err := c.client.Models.GenerateContent(
+		context.Background(),
+		model,
+		genai.Text(prompt),
+		config,
+	)
+	if err != nil {
+		return result, 0, 0, fmt.Errorf("Failed to AI generate %s: %v", name, err)
+	}
+
+	resp := response.Text()
+	if err := json.Unmarshal([]byte(resp), &result); err != nil {
+		return result, 0, 0, fmt.Errorf("Invalid JSON response from Gemini. Please try again.\nGenerated response: %s", resp)
+	}
+	return result, int(response.UsageMetadata.PromptTokenCount), int(response.UsageMetadata.CandidatesTokenCount), nil
+}
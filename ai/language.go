@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LanguageNames maps common locale codes to language names, so the prompt names the language rather than the code
+var LanguageNames = map[string]string{
+	"ar":    "Arabic",
+	"cs":    "Czech",
+	"da":    "Danish",
+	"de":    "German",
+	"el":    "Greek",
+	"en":    "English",
+	"en-gb": "British English",
+	"en-us": "American English",
+	"es":    "Spanish",
+	"fi":    "Finnish",
+	"fr":    "French",
+	"he":    "Hebrew",
+	"hi":    "Hindi",
+	"id":    "Indonesian",
+	"it":    "Italian",
+	"ja":    "Japanese",
+	"ko":    "Korean",
+	"nl":    "Dutch",
+	"no":    "Norwegian",
+	"pl":    "Polish",
+	"pt":    "Portuguese",
+	"pt-br": "Brazilian Portuguese",
+	"ru":    "Russian",
+	"sv":    "Swedish",
+	"th":    "Thai",
+	"tr":    "Turkish",
+	"uk":    "Ukrainian",
+	"vi":    "Vietnamese",
+	"zh":    "Simplified Chinese",
+	"zh-cn": "Simplified Chinese",
+	"zh-tw": "Traditional Chinese",
+}
+
+var languageCodeRegex = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// ValidateLanguage checks that the language is a locale code (e.g. de, ja or pt-BR)
+func ValidateLanguage(language string) error {
+	if !languageCodeRegex.MatchString(language) {
+		return fmt.Errorf("invalid language '%s', expected a locale code (e.g. de, ja or pt-BR)", language)
+	}
+	return nil
+}
+
+// LanguageName returns the name of the language with its code (e.g. "German (de)"), or the code if the language is unknown
+func LanguageName(language string) string {
+	if name, ok := LanguageNames[strings.ToLower(language)]; ok {
+		return fmt.Sprintf("%s (%s)", name, language)
+	}
+	if name, ok := LanguageNames[strings.ToLower(strings.SplitN(language, "-", 2)[0])]; ok {
+		return fmt.Sprintf("%s (%s)", name, language)
+	}
+	return language
+}
+
+// languageRule formats the language section of the built-in prompts, asking to write the fields in the language and
+// to keep the unchanged text (if any) as it is
+func languageRule(language, fields, unchanged string) string {
+	if language == "" {
+		return ""
+	}
+	rule := fmt.Sprintf("\n## Language:\nWrite %s in %s.", fields, LanguageName(language))
+	if unchanged != "" {
+		rule += fmt.Sprintf(" Keep %s unchanged.", unchanged)
+	}
+	return rule + "\n"
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// MigrationTags are the tags of the changes that get an upgrade guide
+var MigrationTags = []string{"breaking", "deprecation"}
+
+var MigrationPrompt = `
+You are a changelog writing assistant. The change below breaks backwards compatibility or deprecates a feature. Based on the change and the diffs of its commits, write an upgrade guide for the users of the software that adheres exactly to the JSON schema.
+
+## Rules:
+- Only use the information provided in the change and the diffs. Do not invent APIs, flags or options.
+- The summary describes what changed and who is affected by it.
+- The before and after snippets show the same usage (code, configuration or command) before and after the change, derived from the diffs. Leave them as empty strings if there is no usage to show.
+- The actions are the steps users must take to upgrade, in order. There must be at least one action.
+- Output must be strictly valid JSON matching the schema. Do not include any explanation or extra text.
+%s
+## Change:
+%s
+
+## Git Commits:
+Each commit is shown below with its hash, message, and code diff separated by "--- COMMIT ---".
+
+%s
+	`
+
+type GenerateMigrationResponse struct {
+	Migration    models.Migration
+	InputTokens  int
+	OutputTokens int
+}
+
+// NeedsMigration returns true if the change is tagged with one of the MigrationTags
+func NeedsMigration(change models.ChangelogChange) bool {
+	return lo.Some(change.Tags, MigrationTags)
+}
+
+// BuildMigrationPrompt builds the prompt used to write the upgrade guide of a breaking or deprecated change from its
+// commits. The language is the locale code of the language of the entry.
+func BuildMigrationPrompt(change models.ChangelogChange, language string, styleGuide string) (string, error) {
+	historyWithDiff, err := git.CommitsHistoryWithDiff(change.Commits)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit history with diff: %v", err)
+	}
+
+	change.Migration = nil
+	changeJSON, err := json.MarshalIndent(change, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal change: %v", err)
+	}
+
+	return fmt.Sprintf(MigrationPrompt, languageRule(language, "the summary and actions", "code, commands and identifiers")+styleGuideSection(styleGuide), changeJSON, historyWithDiff), nil
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
-	"github.com/ammar-ahmed22/chlog/git"
 	"github.com/ammar-ahmed22/chlog/models"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
@@ -26,24 +26,27 @@ func NewOpenAIClient(apiKey string) (*OpenAIClient, error) {
 var _ AIClient = (*OpenAIClient)(nil)
 
 func (c *OpenAIClient) GenerateChangelogEntry(params GenerateChangelogEntryParams) (GenerateChangelogEntryResponse, error) {
+	prompt, err := BuildPrompt(params)
+	if err != nil {
+		return GenerateChangelogEntryResponse{}, err
+	}
+	return c.GenerateChangelogEntryFromPrompt(params.Model, prompt, TagNames(params.Tags))
+}
+
+func (c *OpenAIClient) GenerateChangelogEntryFromPrompt(model, prompt string, tags []string) (GenerateChangelogEntryResponse, error) {
 	ctx := context.Background()
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
 		Name:        "changelog_entry",
 		Description: openai.String("The change log entry for the commit range"),
-		Schema:      models.ChangelogEntrySchema,
+		Schema:      changelogEntrySchema(tags),
 		Strict:      openai.Bool(true),
 	}
 
-	historyWithDiff, err := git.CommitHistoryWithDiff(params.FromCommit, params.ToCommit)
-	if err != nil {
-		return GenerateChangelogEntryResponse{}, fmt.Errorf("failed to get commit history with diff: %v", err)
-	}
-
 	response, err := c.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Model: params.Model,
+		Model: model,
 		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(fmt.Sprintf(Prompt, params.Tags, historyWithDiff)),
+			openai.UserMessage(prompt),
 		},
 		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: schemaParam},
@@ -65,9 +68,68 @@ func (c *OpenAIClient) GenerateChangelogEntry(params GenerateChangelogEntryParam
 		return GenerateChangelogEntryResponse{}, fmt.Errorf("Invalid JSON response from OpenAI. Please try again.")
 	}
 
+	if err := repairTags(&changeLogEntry, tags); err != nil {
+		return GenerateChangelogEntryResponse{}, fmt.Errorf("Invalid tags in response from OpenAI: %v. Please try again.", err)
+	}
+
 	return GenerateChangelogEntryResponse{
 		Entry:        changeLogEntry,
 		InputTokens:  int(response.Usage.PromptTokens),
 		OutputTokens: int(response.Usage.CompletionTokens),
 	}, nil
 }
+
+func (c *OpenAIClient) GenerateMigration(model, prompt string) (GenerateMigrationResponse, error) {
+	migration, inputTokens, outputTokens, err := generateOpenAIStructured[models.Migration](c, model, prompt, "migration", "The upgrade guide of the change", models.MigrationSchema)
+	return GenerateMigrationResponse{Migration: migration, InputTokens: inputTokens, OutputTokens: outputTokens}, err
+}
+
+func (c *OpenAIClient) GenerateSummary(model, prompt string) (GenerateSummaryResponse, error) {
+	summary, inputTokens, outputTokens, err := generateOpenAIStructured[models.ChangelogSummary](c, model, prompt, "changelog_summary", "The summary and highlights of the change log entry", models.SummarySchema)
+	return GenerateSummaryResponse{Summary: summary, InputTokens: inputTokens, OutputTokens: outputTokens}, err
+}
+
+func (c *OpenAIClient) GenerateSecurityAdvisory(model, prompt string) (GenerateSecurityAdvisoryResponse, error) {
+	advisory, inputTokens, outputTokens, err := generateOpenAIStructured[models.SecurityAdvisory](c, model, prompt, "security_advisory", "The advisory information of the security change", models.SecurityAdvisorySchema)
+	return GenerateSecurityAdvisoryResponse{Advisory: advisory, InputTokens: inputTokens, OutputTokens: outputTokens}, err
+}
+
+func (c *OpenAIClient) GenerateTranslation(model, prompt string) (GenerateTranslationResponse, error) {
+	translation, inputTokens, outputTokens, err := generateOpenAIStructured[models.ChangelogEntryTranslation](c, model, prompt, "changelog_translation", "The translation of the change log entry", models.TranslationSchema)
+	return GenerateTranslationResponse{Translation: translation, InputTokens: inputTokens, OutputTokens: outputTokens}, err
+}
+
+// generateOpenAIStructured generates a response of type T that adheres to the schema from a prompt. It returns the
+// response with the number of input and output tokens used.
+func generateOpenAIStructured[T any](c *OpenAIClient, model, prompt, name, description string, schema any) (T, int, int, error) {
+	var result T
+	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
+		Name:        name,
+		Description: openai.String(description),
+		Schema:      schema,
+		Strict:      openai.Bool(true),
+	}
+
+	response, err := c.client.Chat.Completions.New(context.Background(), openai.ChatCompletionNewParams{
+		Model: model,
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.UserMessage(prompt),
+		},
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: schemaParam},
+		},
+	})
+
+	if err != nil {
+		return result, 0, 0, fmt.Errorf("failed to AI generate %s: %v", strings.ReplaceAll(name, "_", " "), err)
+	}
+
+	if len(response.Choices) == 0 {
+		return result, 0, 0, fmt.Errorf("no response from OpenAI")
+	}
+
+	if err := json.Unmarshal([]byte(response.Choices[0].Message.Content), &result); err != nil {
+		return result, 0, 0, fmt.Errorf("Invalid JSON response from OpenAI. Please try again.")
+	}
+	return result, int(response.Usage.PromptTokens), int(response.Usage.CompletionTokens), nil
+}
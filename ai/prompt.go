@@ -0,0 +1,226 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/fatih/color"
+	"github.com/samber/lo"
+)
+
+// RepositoryInfo is the repository metadata available to prompt templates
+type RepositoryInfo struct {
+	Title       string
+	Description string
+	URL         string
+}
+
+type PromptCommit struct {
+	Hash    string
+	Subject string
+	// Details is the output of git show (the commit message and diff)
+	Details string
+	// References are the issues referenced by the commit message (e.g. #123)
+	References []string
+}
+
+// PromptData is the data available to prompt templates, e.g. {{.Version}}, {{range .Commits}}{{.Subject}}{{end}}
+type PromptData struct {
+	Version string
+	Date    string
+	FromRef string
+	ToRef   string
+	Tags    []Tag
+	// TagList is the tags formatted as a Markdown list
+	TagList    string
+	StyleGuide string
+	// Audience is the audience to write the changes for, its name is empty if no audience is set
+	Audience Audience
+	// Language is the name and code of the language to write the changes in (e.g. German (de)), or empty
+	Language   string
+	Repository RepositoryInfo
+	Commits    []PromptCommit
+	// History is the details of all the commits separated by "--- COMMIT ---"
+	History string
+	// References lists the issues referenced by each commit as a Markdown list, or is empty if there are none
+	References string
+}
+
+// The optional sections of the built-in Prompt, they are only rendered when their field is set
+const (
+	audiencePromptSection = `
+{{- if .Audience.Name}}
+
+## Audience:
+Write the changes for the {{.Audience.Name}} audience. {{.Audience.Instructions}}
+{{- end}}`
+	languagePromptSection = `
+{{- if .Language}}
+
+## Language:
+Write the title, description and impact of each change in {{.Language}}. Keep commit hashes, tags and code identifiers unchanged.
+{{- end}}`
+	referencesPromptSection = `
+{{- if .References}}
+
+## Issue References:
+The commits below reference these issues and tickets. Group the commits of the same issue into a single change when they are part of the same change.
+{{.References}}
+{{- end}}`
+	styleGuidePromptSection = `
+{{- if .StyleGuide}}
+
+## Style Guide:
+Follow these additional rules from the project's style guide:
+{{.StyleGuide}}
+{{- end}}`
+)
+
+// promptSections are the optional sections of the built-in Prompt by the field of PromptData they use. They are added
+// to the end of custom templates that do not use the field, so e.g. --language is not silently ignored.
+var promptSections = []struct {
+	field   string
+	section string
+}{
+	{".Audience", audiencePromptSection},
+	{".Language", languagePromptSection},
+	{".References", referencesPromptSection},
+	{".StyleGuide", styleGuidePromptSection},
+}
+
+var promptFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+func parsePromptTemplate(text string) (*template.Template, error) {
+	return template.New("prompt").Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+}
+
+// ValidatePromptTemplate checks that a prompt template parses and executes with sample data
+func ValidatePromptTemplate(text string) error {
+	tmpl, err := parsePromptTemplate(text)
+	if err != nil {
+		return err
+	}
+	sample := PromptData{
+		Version: "1.0.0",
+		Date:    "2006-01-02",
+		Tags:    DefaultTagDefinitions,
+		TagList: FormatTags(DefaultTagDefinitions),
+		Commits: []PromptCommit{{Hash: "abc1234", Subject: "Sample commit", Details: "commit abc1234"}},
+		History: "--- COMMIT ---\ncommit abc1234\n",
+	}
+	return tmpl.Execute(&strings.Builder{}, sample)
+}
+
+// BuildPrompt builds the changelog generation prompt for the commit range of the params, using the custom prompt template
+// of the params or the built-in Prompt
+func BuildPrompt(params GenerateChangelogEntryParams) (string, error) {
+	commits, err := git.CommitRange(params.FromCommit, params.ToCommit)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commits: %v", err)
+	}
+	commits = slices.DeleteFunc(commits, func(commit string) bool {
+		return commit == "" || slices.ContainsFunc(params.ExcludeCommits, func(excluded string) bool {
+			return git.SameCommit(commit, excluded)
+		})
+	})
+
+	data := PromptData{
+		Version:    params.Version,
+		Date:       params.Date,
+		FromRef:    params.FromCommit,
+		ToRef:      params.ToCommit,
+		Tags:       params.Tags,
+		TagList:    FormatTags(params.Tags),
+		StyleGuide: strings.TrimSpace(params.StyleGuide),
+		Audience:   params.Audience,
+		Language:   lo.Ternary(params.Language != "", LanguageName(params.Language), ""),
+		Repository: params.Repository,
+	}
+	var history strings.Builder
+	for _, commit := range commits {
+		details, err := git.CommitDetails(commit)
+		if err != nil {
+			return "", fmt.Errorf("failed to get commit history with diff: %v", err)
+		}
+		subject, _ := git.CommitSubject(commit)
+		promptCommit := PromptCommit{Hash: commit, Subject: subject, Details: details}
+		if len(params.ReferencePatterns) > 0 {
+			references, err := git.CommitReferences(commit, params.ReferencePatterns)
+			if err != nil {
+				return "", fmt.Errorf("failed to get commit references: %v", err)
+			}
+			promptCommit.References = lo.Map(references, func(reference git.Reference, _ int) string {
+				return reference.Text
+			})
+		}
+		data.Commits = append(data.Commits, promptCommit)
+		history.WriteString(fmt.Sprintf("--- COMMIT ---\n%s\n", details))
+	}
+	data.History = history.String()
+	data.References = formatReferences(data.Commits)
+
+	if params.PromptTemplate != "" {
+		prompt, err := executePromptTemplate(withPromptSections(params.PromptTemplate), data)
+		if err == nil {
+			return prompt, nil
+		}
+		fmt.Fprintf(os.Stderr, "%s Error executing the prompt template: %v. Using the built-in prompt\n", color.YellowString("!"), err)
+	}
+
+	prompt, err := executePromptTemplate(Prompt, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute prompt template: %v", err)
+	}
+	return prompt, nil
+}
+
+// withPromptSections adds the promptSections of the fields the custom template does not use to its end
+func withPromptSections(text string) string {
+	for _, section := range promptSections {
+		if !strings.Contains(text, section.field) {
+			text += "\n" + section.section + "\n"
+		}
+	}
+	return text
+}
+
+func executePromptTemplate(text string, data PromptData) (string, error) {
+	tmpl, err := parsePromptTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var prompt strings.Builder
+	if err := tmpl.Execute(&prompt, data); err != nil {
+		return "", err
+	}
+	return prompt.String(), nil
+}
+
+// formatReferences formats the references of the commits as a Markdown list, commits without references are left out
+func formatReferences(commits []PromptCommit) string {
+	lines := []string{}
+	for _, commit := range commits {
+		if len(commit.References) > 0 {
+			lines = append(lines, fmt.Sprintf("  - %s: %s", commit.Hash, strings.Join(commit.References, ", ")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// styleGuideSection formats the style guide as a section of the built-in prompts
+func styleGuideSection(styleGuide string) string {
+	styleGuide = strings.TrimSpace(styleGuide)
+	if styleGuide == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n## Style Guide:\nFollow these additional rules from the project's style guide:\n%s\n", styleGuide)
+}
@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithPromptSections(t *testing.T) {
+	data := PromptData{
+		Audience:   Audience{Name: "developer", Instructions: "Mention the APIs."},
+		Language:   "German (de)",
+		References: "  - abc1234: #12",
+		StyleGuide: "Use present tense.",
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "no fields",
+			template: "Custom prompt",
+			want:     []string{"Custom prompt", "## Audience:", "German (de)", "abc1234: #12", "Use present tense."},
+		},
+		{
+			name:     "uses language",
+			template: "Write in {{.Language}}",
+			want:     []string{"Write in German (de)", "## Audience:", "## Issue References:", "## Style Guide:"},
+			notWant:  []string{"## Language:"},
+		},
+		{
+			name:     "uses all fields",
+			template: "{{.Audience.Name}} {{.Language}} {{.References}} {{.StyleGuide}}",
+			notWant:  []string{"## Audience:", "## Language:", "## Issue References:", "## Style Guide:"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prompt, err := executePromptTemplate(withPromptSections(test.template), data)
+			if err != nil {
+				t.Fatalf("executePromptTemplate() error: %v", err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("prompt does not contain %q:\n%s", want, prompt)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(prompt, notWant) {
+					t.Errorf("prompt contains %q:\n%s", notWant, prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestWithPromptSectionsEmptyFields(t *testing.T) {
+	prompt, err := executePromptTemplate(withPromptSections("Custom prompt"), PromptData{})
+	if err != nil {
+		t.Fatalf("executePromptTemplate() error: %v", err)
+	}
+	if strings.TrimSpace(prompt) != "Custom prompt" {
+		t.Errorf("prompt = %q, want only the template", prompt)
+	}
+}
@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+	"github.com/samber/lo"
+	"google.golang.org/genai"
+)
+
+// GeminiSchema converts a JSON schema generated from the models (see models.GenerateResponseSchema) to a Gemini
+// response schema, so every provider uses the same schema. Only the keywords used by the response schemas are
+// supported: type, description, enum, items, properties and required.
+func GeminiSchema(schema *jsonschema.Schema) (*genai.Schema, error) {
+	geminiType, ok := geminiSchemaTypes[schema.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema type '%s'", schema.Type)
+	}
+
+	geminiSchema := &genai.Schema{
+		Type:        geminiType,
+		Description: schema.Description,
+	}
+	if len(schema.Enum) > 0 {
+		geminiSchema.Format = "enum"
+		geminiSchema.Enum = lo.Map(schema.Enum, func(value any, _ int) string {
+			return fmt.Sprint(value)
+		})
+	}
+
+	if schema.Items != nil {
+		items, err := GeminiSchema(schema.Items)
+		if err != nil {
+			return nil, err
+		}
+		geminiSchema.Items = items
+	}
+
+	if schema.Properties != nil {
+		geminiSchema.Properties = map[string]*genai.Schema{}
+		for pair := schema.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			property, err := GeminiSchema(pair.Value)
+			if err != nil {
+				return nil, fmt.Errorf("property '%s': %v", pair.Key, err)
+			}
+			geminiSchema.Properties[pair.Key] = property
+			// Gemini generates the properties in alphabetical order unless an ordering is given
+			geminiSchema.PropertyOrdering = append(geminiSchema.PropertyOrdering, pair.Key)
+		}
+		geminiSchema.Required = schema.Required
+	}
+	return geminiSchema, nil
+}
+
+var geminiSchemaTypes = map[string]genai.Type{
+	"string":  genai.TypeString,
+	"boolean": genai.TypeBoolean,
+	"integer": genai.TypeInteger,
+	"number":  genai.TypeNumber,
+	"array":   genai.TypeArray,
+	"object":  genai.TypeObject,
+}
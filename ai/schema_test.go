@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/invopop/jsonschema"
+	"google.golang.org/genai"
+)
+
+// TestGeminiSchemaMatchesResponseSchemas checks that the Gemini schemas have the same properties (in the same order),
+// required properties and enums as the JSON schemas sent to OpenAI
+func TestGeminiSchemaMatchesResponseSchemas(t *testing.T) {
+	schemas := map[string]*jsonschema.Schema{
+		"changelog entry":             models.ChangelogEntrySchema,
+		"changelog entry (with tags)": changelogEntrySchema([]string{"added", "fixed", "perf"}),
+		"migration":                   models.MigrationSchema,
+		"summary":                     models.SummarySchema,
+		"security":                    models.SecurityAdvisorySchema,
+	}
+	for name, schema := range schemas {
+		t.Run(name, func(t *testing.T) {
+			geminiSchema, err := GeminiSchema(schema)
+			if err != nil {
+				t.Fatalf("GeminiSchema() error: %v", err)
+			}
+			for _, err := range compareSchemas(schema, geminiSchema, "$") {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGeminiSchemaRestrictsTags(t *testing.T) {
+	tags := []string{"added", "fixed"}
+	geminiSchema, err := GeminiSchema(changelogEntrySchema(tags))
+	if err != nil {
+		t.Fatalf("GeminiSchema() error: %v", err)
+	}
+	enum := geminiSchema.Properties["changes"].Items.Properties["tags"].Items.Enum
+	if !slices.Equal(enum, tags) {
+		t.Errorf("tags enum = %v, want %v", enum, tags)
+	}
+}
+
+func TestGeminiSchemaUnsupportedType(t *testing.T) {
+	if _, err := GeminiSchema(&jsonschema.Schema{Type: "null"}); err == nil {
+		t.Error("GeminiSchema() of a null schema: expected an error")
+	}
+}
+
+// compareSchemas returns the differences between the JSON schema and the Gemini schema at the path
+func compareSchemas(schema *jsonschema.Schema, geminiSchema *genai.Schema, path string) []error {
+	var errs []error
+	if geminiSchema == nil {
+		return []error{fmt.Errorf("%s: missing in the Gemini schema", path)}
+	}
+	if geminiSchemaTypes[schema.Type] != geminiSchema.Type {
+		errs = append(errs, fmt.Errorf("%s: type %s, want %s", path, geminiSchema.Type, schema.Type))
+	}
+	if geminiSchema.Description != schema.Description {
+		errs = append(errs, fmt.Errorf("%s: description %q, want %q", path, geminiSchema.Description, schema.Description))
+	}
+
+	enum := []string{}
+	for _, value := range schema.Enum {
+		enum = append(enum, fmt.Sprint(value))
+	}
+	if !slices.Equal(enum, append([]string{}, geminiSchema.Enum...)) {
+		errs = append(errs, fmt.Errorf("%s: enum %v, want %v", path, geminiSchema.Enum, enum))
+	}
+
+	if schema.Items != nil {
+		errs = append(errs, compareSchemas(schema.Items, geminiSchema.Items, path+"[]")...)
+	} else if geminiSchema.Items != nil {
+		errs = append(errs, fmt.Errorf("%s: unexpected items in the Gemini schema", path))
+	}
+
+	properties := []string{}
+	if schema.Properties != nil {
+		for pair := schema.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			properties = append(properties, pair.Key)
+			errs = append(errs, compareSchemas(pair.Value, geminiSchema.Properties[pair.Key], path+"."+pair.Key)...)
+		}
+	}
+	if len(geminiSchema.Properties) != len(properties) {
+		errs = append(errs, fmt.Errorf("%s: %d properties, want %d", path, len(geminiSchema.Properties), len(properties)))
+	}
+	if !slices.Equal(append([]string{}, geminiSchema.PropertyOrdering...), properties) {
+		errs = append(errs, fmt.Errorf("%s: property ordering %v, want %v", path, geminiSchema.PropertyOrdering, properties))
+	}
+	if !slices.Equal(append([]string{}, geminiSchema.Required...), append([]string{}, schema.Required...)) {
+		errs = append(errs, fmt.Errorf("%s: required %v, want %v", path, geminiSchema.Required, schema.Required))
+	}
+	return errs
+}
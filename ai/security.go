@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// SecurityTag is the tag of the changes that get advisory information
+const SecurityTag = "security"
+
+var SecurityPrompt = `
+You are a security advisory assistant. The change below fixes a vulnerability or improves the security of the software. Based on the change and the diffs of its commits, describe the vulnerability so it adheres exactly to the JSON schema.
+
+## Rules:
+- Only use the information provided in the change, the advisory IDs and the diffs. Do not invent details.
+- The summary is a single line describing the vulnerability that was fixed (e.g. "Path traversal in the file upload endpoint").
+- The component is the affected part of the software (e.g. a package, module, command or endpoint), as specific as the diffs allow.
+- The severity is your estimate of how severe the vulnerability was: low, moderate, high or critical. Consider how it can be exploited and what an attacker gains, as CVSS does.
+- Output must be strictly valid JSON matching the schema. Do not include any explanation or extra text.
+%s
+## Advisory IDs:
+%s
+
+## Change:
+%s
+
+## Git Commits:
+Each commit is shown below with its hash, message, and code diff separated by "--- COMMIT ---".
+
+%s
+	`
+
+type GenerateSecurityAdvisoryResponse struct {
+	Advisory     models.SecurityAdvisory
+	InputTokens  int
+	OutputTokens int
+}
+
+// NeedsSecurityAdvisory returns true if the change is tagged with the SecurityTag
+func NeedsSecurityAdvisory(change models.ChangelogChange) bool {
+	return lo.Contains(change.Tags, SecurityTag)
+}
+
+// BuildSecurityPrompt builds the prompt used to describe the vulnerability fixed by a security change from its commits
+// and the advisory IDs (e.g. CVE IDs) referenced by them. The language is the locale code of the language of the entry.
+func BuildSecurityPrompt(change models.ChangelogChange, ids []string, language string, styleGuide string) (string, error) {
+	historyWithDiff, err := git.CommitsHistoryWithDiff(change.Commits)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit history with diff: %v", err)
+	}
+
+	change.Security = nil
+	changeJSON, err := json.MarshalIndent(change, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal change: %v", err)
+	}
+
+	advisoryIDs := "None"
+	if len(ids) > 0 {
+		advisoryIDs = strings.Join(ids, ", ")
+	}
+
+	return fmt.Sprintf(SecurityPrompt, languageRule(language, "the summary", "code identifiers")+styleGuideSection(styleGuide), advisoryIDs, changeJSON, historyWithDiff), nil
+}
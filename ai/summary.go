@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// DefaultHighlights is the default number of highlights of an entry
+const DefaultHighlights = 3
+
+var SummaryPrompt = `
+You are a changelog writing assistant. Based on the changes of the changelog entry below, write a summary of the release and pick its highlights so they adhere exactly to the JSON schema.
+
+## Rules:
+- Only use the information provided in the changes. Do not invent details.
+- The summary is a short, human friendly paragraph (2 to 4 sentences) giving an overview of the release, suitable for a release announcement.
+- The highlights are the IDs of the %d most important changes for the users of the software, most important first. Prefer changes with a major importance. Use fewer if the entry has fewer changes.
+- Only use IDs of changes in the entry.
+- Output must be strictly valid JSON matching the schema. Do not include any explanation or extra text.
+%s
+## Changelog Entry:
+%s
+	`
+
+type GenerateSummaryResponse struct {
+	Summary      models.ChangelogSummary
+	InputTokens  int
+	OutputTokens int
+}
+
+// BuildSummaryPrompt builds the prompt used to write the summary of an entry and pick up to highlights of its changes.
+// The language is the locale code of the language of the entry.
+func BuildSummaryPrompt(entry models.ChangelogEntry, highlights int, language string, styleGuide string) (string, error) {
+	summarized := models.ChangelogEntry{
+		Version: entry.Version,
+		Date:    entry.Date,
+		Changes: lo.Map(entry.Changes, func(change models.ChangelogChange, _ int) models.ChangelogChange {
+			return models.ChangelogChange{
+				ID:          change.ID,
+				Title:       change.Title,
+				Description: change.Description,
+				Impact:      change.Impact,
+				Commits:     change.Commits,
+				Tags:        change.Tags,
+				Importance:  change.Importance,
+			}
+		}),
+	}
+	entryJSON, err := json.MarshalIndent(summarized, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal changelog entry: %v", err)
+	}
+
+	return fmt.Sprintf(SummaryPrompt, highlights, languageRule(language, "the summary", "")+styleGuideSection(styleGuide), entryJSON), nil
+}
@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/invopop/jsonschema"
+	"github.com/samber/lo"
+)
+
+// Tag is a tag changes can be tagged with. The description tells the model when to use it.
+type Tag struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+var DefaultTagDefinitions = []Tag{
+	{Name: "feature", Description: "A new feature or capability"},
+	{Name: "fix", Description: "A bug fix"},
+	{Name: "improvement", Description: "An improvement to an existing feature (e.g. performance, usability)"},
+	{Name: "deprecation", Description: "A feature that is deprecated and will be removed in the future"},
+	{Name: "security", Description: "A fix or improvement related to security"},
+	{Name: "breaking", Description: "A change that breaks backwards compatibility (e.g. removed or renamed features)"},
+	{Name: "documentation", Description: "A change to the documentation"},
+}
+
+var DefaultTags = TagNames(DefaultTagDefinitions)
+
+func TagNames(tags []Tag) []string {
+	return lo.Map(tags, func(tag Tag, _ int) string {
+		return tag.Name
+	})
+}
+
+// FormatTags formats the tags as a Markdown list for the prompts
+func FormatTags(tags []Tag) string {
+	lines := lo.Map(tags, func(tag Tag, _ int) string {
+		if tag.Description == "" {
+			return fmt.Sprintf("  - %s", tag.Name)
+		}
+		return fmt.Sprintf("  - %s: %s", tag.Name, tag.Description)
+	})
+	return strings.Join(lines, "\n")
+}
+
+// changelogEntrySchema returns the JSON schema of a changelog entry, restricting the tags of the changes to the given tags
+func changelogEntrySchema(tags []string) *jsonschema.Schema {
+	if len(tags) == 0 {
+		return models.ChangelogEntrySchema
+	}
+
+	schema := models.GenerateResponseSchema[models.ChangelogEntry]()
+	changes, _ := schema.Properties.Get("changes")
+	changeTags, _ := changes.Items.Properties.Get("tags")
+	changeTags.Items.Enum = lo.Map(tags, func(tag string, _ int) any {
+		return tag
+	})
+	return schema
+}
+
+// repairTags fixes the case of tags that match an allowed tag case-insensitively and removes unknown tags.
+// Returns an error if a change is left without any tags.
+func repairTags(entry *models.ChangelogEntry, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	for i, change := range entry.Changes {
+		repaired := []string{}
+		for _, tag := range change.Tags {
+			allowed, found := lo.Find(tags, func(allowed string) bool {
+				return strings.EqualFold(allowed, strings.TrimSpace(tag))
+			})
+			if found {
+				repaired = append(repaired, allowed)
+			}
+		}
+		if len(repaired) == 0 {
+			return fmt.Errorf("change '%s' has no valid tags (got %v, allowed tags are: %s)", change.Title, change.Tags, strings.Join(tags, ", "))
+		}
+		entry.Changes[i].Tags = lo.Uniq(repaired)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// advisoriesCmd represents the advisories command
+var advisoriesCmd = &cobra.Command{
+	Use:   "advisories [FILE]",
+	Short: "Render the security changes of a changelog file as OSV advisories",
+	Long: `Render the security changes of a changelog file as advisory stubs in the OSV format (https://ossf.github.io/osv-schema/).
+
+An advisory is rendered for each change with advisory information (see chlog generate). Its ID is the first CVE or GHSA ID referenced by the commits of the change, or a CHLOG-<version>-<change ID> placeholder (marked with placeholder_id in the database_specific field). The affected Git range is fixed by the newest commit of the change, and the estimated severity and affected component are kept in the database_specific field. Changes that reference the same advisory ID (e.g. a fix and its backport) are merged into one advisory, with the other changes listed under merged in the database_specific field. The published and modified timestamps are the dates of the entries, and are left out (with a warning) for undated entries.
+
+The advisories are stubs: review them and fill in the affected packages and versions before publishing.
+
+The file defaults to the 'file' key of the config file. The repository URL is read from the 'repository' field of the file, or the origin remote of the Git repository.
+
+Example:
+	chlog advisories changelog.json --version 1.2.0
+	chlog advisories --output-dir advisories`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		err = utils.LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("Error loading config file '%s': %v", configPath, err)
+		}
+
+		var file string
+		if len(args) > 0 {
+			file = args[0]
+		} else {
+			file, err = utils.ParseFileFlag(cmd, configPath)
+			if err != nil {
+				return err
+			}
+		}
+		if file == "" {
+			return fmt.Errorf("No changelog file specified. Pass it as an argument or set the 'file' key in the config file")
+		}
+
+		version, err := cmd.Flags().GetString("version")
+		if err != nil {
+			return err
+		}
+
+		outputDir, err := cmd.Flags().GetString("output-dir")
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("Error reading changelog file '%s': %v", file, err)
+		}
+
+		changelogFile, err := utils.ParseAndValidateChangelogFile(file)
+		if err != nil {
+			return err
+		}
+
+		entries := changelogFile.Entries
+		if version != "" {
+			index := utils.FindChangelogEntry(entries, version)
+			if index == -1 {
+				return fmt.Errorf("Version '%s' not found in the changelog file", version)
+			}
+			entries = []models.ChangelogEntry{entries[index]}
+		}
+
+		repository := utils.ChangelogRepositoryInfo(changelogFile)
+		advisories := utils.OSVAdvisories(entries, repository.URL)
+		for _, advisory := range advisories {
+			// The modified timestamp is required by OSV, but it is only known for dated entries
+			if advisory.Modified == "" {
+				utils.Eprintf("%s Advisory '%s' has no modified timestamp since version %s has no date. Set it before publishing\n", color.YellowString("!"), advisory.ID, advisory.DatabaseSpecific.Version)
+			}
+		}
+
+		if outputDir == "" {
+			output, err := json.MarshalIndent(advisories, "", "  ")
+			if err != nil {
+				return fmt.Errorf("Error generating JSON: %v", err)
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+
+		if len(advisories) == 0 {
+			utils.Eprintf("%s No security changes with advisory information found in '%s'\n", color.YellowString("!"), file)
+			return nil
+		}
+
+		err = os.MkdirAll(outputDir, 0755)
+		if err != nil {
+			return fmt.Errorf("Error creating directory '%s': %v", outputDir, err)
+		}
+		for _, advisory := range advisories {
+			output, err := json.MarshalIndent(advisory, "", "  ")
+			if err != nil {
+				return fmt.Errorf("Error generating JSON: %v", err)
+			}
+			path := filepath.Join(outputDir, advisory.ID+".json")
+			err = os.WriteFile(path, append(output, '\n'), 0644)
+			if err != nil {
+				return fmt.Errorf("Error writing file '%s': %v", path, err)
+			}
+		}
+		utils.Eprintf("%s Rendered %d advisories from changelog file '%s' to '%s'\n", color.GreenString("\u2713"), len(advisories), file, outputDir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(advisoriesCmd)
+
+	advisoriesCmd.Flags().StringP("config", "c", "", "Path to config file (optional, chlog.yaml will be loaded if present in the current directory)")
+	advisoriesCmd.Flags().String("file", "", "Path to changelog file (JSON, YAML or TOML), used if FILE is not specified")
+	advisoriesCmd.Flags().String("version", "", "Only render the advisories of this version")
+	advisoriesCmd.Flags().StringP("output-dir", "o", "", "Write each advisory to <id>.json in this directory instead of printing a JSON array to stdout")
+}
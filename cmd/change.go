@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/fatih/color"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// changeCmd represents the change command
+var changeCmd = &cobra.Command{
+	Use:   "change",
+	Short: "Edit the changes of a changelog entry",
+}
+
+// changeRmCmd represents the change rm command
+var changeRmCmd = &cobra.Command{
+	Use:   "rm <VERSION> <ID>",
+	Short: "Remove a change from the entry of the specified version",
+	Long: `Remove the change with the specified ID from the entry of the specified version, along with its highlight, audience variants and translations.
+
+Example:
+	chlog change rm 1.2.0 add-new-feature --file changelog.json`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, id := args[0], args[1]
+
+		flags, err := utils.ParseEditFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer flags.ChangelogFile.Unlock()
+
+		updated, err := utils.RemoveChangelogChange(flags.ChangelogFile.Entries, version, id)
+		if err != nil {
+			return err
+		}
+
+		err = writeEditedChangelog(flags, updated)
+		if err != nil {
+			return err
+		}
+
+		utils.Eprintf("%s Removed change '%s' from version %s\n", color.GreenString("\u2713"), id, color.CyanString(version))
+		return nil
+	},
+}
+
+// changeMvCmd represents the change mv command
+var changeMvCmd = &cobra.Command{
+	Use:   "mv <VERSION> <ID> <TARGET_VERSION>",
+	Short: "Move a change to the entry of another version",
+	Long: `Move the change with the specified ID from the entry of the specified version to the end of the entry of the target version. Its audience variants and translations are moved with it.
+
+Example:
+	chlog change mv 1.2.0 add-new-feature 1.3.0 --file changelog.json`,
+	Args:         cobra.ExactArgs(3),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, id, targetVersion := args[0], args[1], args[2]
+
+		flags, err := utils.ParseEditFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer flags.ChangelogFile.Unlock()
+
+		updated, err := utils.MoveChangelogChange(flags.ChangelogFile.Entries, version, id, targetVersion)
+		if err != nil {
+			return err
+		}
+
+		err = writeEditedChangelog(flags, updated)
+		if err != nil {
+			return err
+		}
+
+		utils.Eprintf("%s Moved change '%s' from version %s to %s\n", color.GreenString("\u2713"), id, color.CyanString(version), color.CyanString(targetVersion))
+		return nil
+	},
+}
+
+// changeRetagCmd represents the change retag command
+var changeRetagCmd = &cobra.Command{
+	Use:   "retag <VERSION> <ID>",
+	Short: "Add or remove tags of a change",
+	Long: `Add or remove tags of the change with the specified ID in the entry of the specified version.
+
+Example:
+	chlog change retag 1.2.0 remove-legacy-api --add breaking --remove improvement --file changelog.json`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, id := args[0], args[1]
+
+		add, err := cmd.Flags().GetStringSlice("add")
+		if err != nil {
+			return err
+		}
+		remove, err := cmd.Flags().GetStringSlice("remove")
+		if err != nil {
+			return err
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			return fmt.Errorf("Specify the tags to add or remove with '--add' or '--remove'")
+		}
+
+		flags, err := utils.ParseEditFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer flags.ChangelogFile.Unlock()
+
+		allowedTags := ai.TagNames(flags.Tags)
+		for _, tag := range add {
+			if !lo.Contains(allowedTags, tag) {
+				return fmt.Errorf("Invalid tag '%s'. Allowed tags are: %s", tag, strings.Join(allowedTags, ", "))
+			}
+		}
+
+		updated, err := utils.RetagChangelogChange(flags.ChangelogFile.Entries, version, id, add, remove)
+		if err != nil {
+			return err
+		}
+
+		err = writeEditedChangelog(flags, updated)
+		if err != nil {
+			return err
+		}
+
+		utils.Eprintf("%s Retagged change '%s' of version %s\n", color.GreenString("\u2713"), id, color.CyanString(version))
+		return nil
+	},
+}
+
+// changeEditCmd represents the change edit command
+var changeEditCmd = &cobra.Command{
+	Use:   "edit <VERSION> <ID>",
+	Short: "Edit a change in your editor",
+	Long: `Open the change with the specified ID in your editor ($VISUAL or $EDITOR) as YAML.
+
+The change is validated when the editor is closed. If it is not valid, the editor is opened again with the error. Delete all the contents to cancel the edit.
+
+Example:
+	chlog change edit 1.2.0 add-new-feature --file changelog.json`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, id := args[0], args[1]
+
+		flags, err := utils.ParseEditFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer flags.ChangelogFile.Unlock()
+
+		entries := flags.ChangelogFile.Entries
+		entryIndex := utils.FindChangelogEntry(entries, version)
+		if entryIndex == -1 {
+			return fmt.Errorf("Version '%s' not found in the changelog file", version)
+		}
+		changeIndex := utils.FindChangelogChange(entries[entryIndex], id)
+		if changeIndex == -1 {
+			return fmt.Errorf("Change '%s' not found in version '%s'", id, version)
+		}
+
+		original, err := utils.MarshalChangelogChangeYAML(entries[entryIndex].Changes[changeIndex])
+		if err != nil {
+			return fmt.Errorf("Error generating YAML: %v", err)
+		}
+
+		header := fmt.Sprintf("# Editing change '%s' of version %s. Save and close the editor to apply the changes.\n# Delete all the contents to cancel. Allowed tags are: %s\n", id, version, strings.Join(ai.TagNames(flags.Tags), ", "))
+		contents := append([]byte(header), original...)
+		for {
+			edited, err := utils.EditInEditor("change.yaml", contents)
+			if err != nil {
+				return err
+			}
+
+			body := stripCommentHeader(edited)
+			if len(bytes.TrimSpace(body)) == 0 {
+				utils.Eprintf("%s Edit cancelled\n", color.YellowString("!"))
+				return nil
+			}
+			if bytes.Equal(body, original) {
+				utils.Eprintf("%s No changes made\n", color.YellowString("!"))
+				return nil
+			}
+
+			change, err := utils.UnmarshalChangelogChangeYAML(body)
+			if err == nil {
+				var updated []models.ChangelogEntry
+				updated, err = utils.ReplaceChangelogChange(entries, version, id, change, ai.TagNames(flags.Tags))
+				if err == nil {
+					err = writeEditedChangelog(flags, updated)
+					if err != nil {
+						return err
+					}
+					utils.Eprintf("%s Updated change '%s' of version %s\n", color.GreenString("\u2713"), change.ID, color.CyanString(version))
+					return nil
+				}
+			}
+
+			// Reopen the editor with the error, unless the editor did not change anything since the last attempt
+			if bytes.Equal(edited, contents) {
+				return fmt.Errorf("Invalid change: %v", err)
+			}
+			utils.Eprintf("%s Invalid change: %v\n", color.RedString("\u2717"), err)
+			contents = append([]byte(fmt.Sprintf("%s# Error: %s\n", header, strings.ReplaceAll(err.Error(), "\n", " "))), body...)
+		}
+	},
+}
+
+// stripCommentHeader removes the comment lines at the start of the edited contents
+func stripCommentHeader(contents []byte) []byte {
+	for bytes.HasPrefix(contents, []byte("#")) {
+		_, rest, found := bytes.Cut(contents, []byte("\n"))
+		if !found {
+			return nil
+		}
+		contents = rest
+	}
+	return contents
+}
+
+func init() {
+	rootCmd.AddCommand(changeCmd)
+	changeCmd.AddCommand(changeRmCmd)
+	changeCmd.AddCommand(changeMvCmd)
+	changeCmd.AddCommand(changeRetagCmd)
+	changeCmd.AddCommand(changeEditCmd)
+
+	addEditFlags(changeCmd)
+	changeRetagCmd.Flags().StringSlice("add", []string{}, "Tags to add (can be repeated or comma-separated)")
+	changeRetagCmd.Flags().StringSlice("remove", []string{}, "Tags to remove (can be repeated or comma-separated)")
+}
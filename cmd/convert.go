@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert <INPUT> <OUTPUT>",
+	Short: "Convert a changelog file between JSON, YAML and TOML",
+	Long: `Convert a changelog file between JSON, YAML and TOML formats.
+
+The formats are detected from the file extensions (.json, .yaml/.yml, .toml). Any additional top-level keys (e.g. title, description) are kept in the converted file.
+
+Example:
+	chlog convert changelog.json changelog.yaml`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, output := args[0], args[1]
+
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(input); err != nil {
+			return fmt.Errorf("Error reading changelog file '%s': %v", input, err)
+		}
+
+		if _, err := os.Stat(output); err == nil && !force {
+			return fmt.Errorf("Output file '%s' already exists. Use --force to overwrite it", output)
+		}
+
+		err = utils.ConvertChangelogFile(input, output)
+		if err != nil {
+			return err
+		}
+
+		utils.Eprintf("%s Converted '%s' to '%s'\n", color.GreenString("\u2713"), input, output)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().Bool("force", false, "Overwrite the output file if it already exists")
+}
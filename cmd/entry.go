@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// entryCmd represents the entry command
+var entryCmd = &cobra.Command{
+	Use:   "entry",
+	Short: "Edit the entries of a changelog file",
+}
+
+// entryRmCmd represents the entry rm command
+var entryRmCmd = &cobra.Command{
+	Use:   "rm <VERSION>",
+	Short: "Remove the entry of the specified version",
+	Long: `Remove the entry of the specified version from the changelog file.
+
+Example:
+	chlog entry rm 1.2.0 --file changelog.json`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version := args[0]
+
+		flags, err := utils.ParseEditFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer flags.ChangelogFile.Unlock()
+
+		updated, err := utils.RemoveChangelogEntry(flags.ChangelogFile.Entries, version)
+		if err != nil {
+			return err
+		}
+
+		err = writeEditedChangelog(flags, updated)
+		if err != nil {
+			return err
+		}
+
+		utils.Eprintf("%s Removed version %s from changelog file '%s'\n", color.GreenString("\u2713"), color.CyanString(version), flags.ChangelogFile.Path)
+		return nil
+	},
+}
+
+// writeEditedChangelog writes the entries edited by the entry and change commands to the changelog file
+func writeEditedChangelog(flags *utils.EditFlags, entries []models.ChangelogEntry) error {
+	utils.RefreshContributors(entries)
+	err := utils.WriteChangelogFile(flags.ChangelogFile, entries, utils.WriteChangelogOptions{Backup: flags.Backup})
+	if err != nil {
+		return fmt.Errorf("Error writing changelog file '%s': %v", flags.ChangelogFile.Path, err)
+	}
+	return nil
+}
+
+// addEditFlags adds the flags shared by the commands that edit an existing changelog file
+func addEditFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("config", "c", "", "Path to config file (optional, chlog.yaml will be loaded if present in the current directory)")
+	cmd.PersistentFlags().String("file", "", "Path to changelog file (JSON, YAML or TOML) to edit")
+	cmd.PersistentFlags().Bool("backup", false, "Keep a copy of the previous changelog file contents at <file>.bak")
+}
+
+func init() {
+	rootCmd.AddCommand(entryCmd)
+	entryCmd.AddCommand(entryRmCmd)
+
+	addEditFlags(entryCmd)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ammar-ahmed22/chlog/utils"
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
@@ -26,27 +27,53 @@ var generateCmd = &cobra.Command{
 			return err
 		}
 
+		flags, err := utils.ParseGenerateFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer flags.ExistingChangelogFile.Unlock()
+
 		var version string
-		if len(args) > 0 {
+		if flags.Unreleased {
+			if len(args) > 0 {
+				return fmt.Errorf("A version cannot be specified with '--unreleased'. Use 'chlog release <VERSION>' to release the %s entry", utils.UnreleasedVersion)
+			}
+			version = utils.UnreleasedVersion
+		} else if len(args) > 0 {
 			version = args[0]
 		} else {
 			version = time.Now().Format("2006-01-02")
 		}
 
-		flags, err := utils.ParseGenerateFlags(cmd)
-		if err != nil {
-			return err
+		// Commits already in the Unreleased entry are not generated again
+		var excludeCommits []string
+		if flags.Unreleased && flags.ExistingChangelogFile != nil {
+			excludeCommits = utils.UnreleasedCommits(flags.ExistingChangelogFile.Entries)
 		}
 
-		aiClient, err := ai.NewAIClient(flags.Provider, flags.APIKey)
-		if err != nil {
-			return err
+		// Fail before generating the entry if it cannot be written to the changelog file
+		if !flags.DryRun && !flags.Unreleased && flags.ExistingChangelogFile != nil && flags.OnConflict == utils.ConflictError {
+			_, _, err := utils.UpsertChangelogEntry(flags.ExistingChangelogFile.Entries, models.ChangelogEntry{Version: version}, utils.ConflictError)
+			if err != nil {
+				return err
+			}
 		}
 
 		logs, err := git.LogRange(flags.From, flags.To)
 		if err != nil {
 			return fmt.Errorf("Error getting git log: %v", err)
 		}
+		logs = lo.Filter(logs, func(log string, _ int) bool {
+			hash := strings.SplitN(log, " ", 2)[0]
+			return hash != "" && !lo.ContainsBy(excludeCommits, func(commit string) bool {
+				return git.SameCommit(hash, commit)
+			})
+		})
+
+		if flags.Unreleased && len(logs) == 0 {
+			utils.Eprintf("%s No new commits to add to the %s entry\n", color.YellowString("!"), utils.UnreleasedVersion)
+			return nil
+		}
 
 		if flags.Verbose {
 			utils.Eprintf("\u2192 Generating changelog entry %s\n", color.CyanString(version))
@@ -59,6 +86,41 @@ var generateCmd = &cobra.Command{
 			}
 		}
 
+		params := ai.GenerateChangelogEntryParams{
+			FromCommit:        flags.From,
+			ToCommit:          flags.To,
+			Model:             flags.Model,
+			Version:           version,
+			Date:              flags.Date,
+			Tags:              flags.Tags,
+			PromptTemplate:    flags.PromptTemplate,
+			StyleGuide:        flags.StyleGuide,
+			Language:          flags.Language,
+			ReferencePatterns: flags.ReferencePatterns,
+			Repository:        utils.ChangelogRepositoryInfo(flags.ExistingChangelogFile),
+			ExcludeCommits:    excludeCommits,
+		}
+
+		// The entry is generated once per audience, the first one for the changes of the entry and the others for its variants
+		audiences := flags.Audiences
+		if len(audiences) == 0 {
+			audiences = []ai.Audience{{}}
+		}
+		audienceParams := lo.Map(audiences, func(audience ai.Audience, _ int) ai.GenerateChangelogEntryParams {
+			audienceParams := params
+			audienceParams.Audience = audience
+			return audienceParams
+		})
+
+		if flags.DryRun {
+			return printDryRun(flags, audienceParams)
+		}
+
+		aiClient, err := ai.NewAIClient(flags.Provider, flags.APIKey)
+		if err != nil {
+			return err
+		}
+
 		spnr := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
 		spnr.Writer = os.Stderr
 		if flags.Verbose {
@@ -68,77 +130,386 @@ var generateCmd = &cobra.Command{
 			defer spnr.Stop()
 		}
 
-		response, err := aiClient.GenerateChangelogEntry(ai.GenerateChangelogEntryParams{
-			FromCommit: flags.From,
-			ToCommit:   flags.To,
-			Model:      flags.Model,
-			Version:    version,
-			Date:       flags.Date,
-			Tags:       ai.DefaultTags,
-		})
+		if flags.Verbose && audienceParams[0].Audience.Name != "" {
+			spnr.Suffix = fmt.Sprintf(" AI Generating changelog entry for the %s audience...", audienceParams[0].Audience.Name)
+		}
+		response, err := aiClient.GenerateChangelogEntry(audienceParams[0])
+		spnr.Stop()
 		if err != nil {
 			return fmt.Errorf("Error generating changelog: %v", err)
 		}
+		response.Entry.Audience = audienceParams[0].Audience.Name
+		err = validateGeneratedCommits(audienceParams[0], flags.OnMissingCommits, flags.Verbose, aiClient, &response)
+		if err != nil {
+			return err
+		}
 
 		response.Entry.Version = version
 		response.Entry.Date = flags.Date
+		if flags.Unreleased {
+			response.Entry.Date = ""
+		}
+		response.Entry.Language = flags.Language
 		response.Entry.FromRef = flags.From
 		response.Entry.ToRef = flags.To
-		// Add id to each change
-		for i, change := range response.Entry.Changes {
-			response.Entry.Changes[i].ID = utils.TruncatedKebabCase(change.Title, 40)
+		// Add a unique id to each change. The IDs of an entry of the same version can only be reused when it is replaced,
+		// a merged entry keeps its changes and drops the new changes with the same ID as one of them
+		usedIDs := map[string]bool{}
+		if flags.ExistingChangelogFile != nil {
+			excludedVersion := lo.Ternary(flags.OnConflict == utils.ConflictReplace && !flags.Unreleased, version, "")
+			usedIDs = utils.ChangelogChangeIDs(flags.ExistingChangelogFile.Entries, excludedVersion)
+		}
+		utils.AssignChangeIDs(response.Entry.Changes, version, flags.IDStrategy, usedIDs)
+
+		// The changes are reviewed before the variants are generated, so the variants only cover the accepted changes
+		var rejectedCommits []string
+		if flags.Review {
+			commits, _, err := rangeCommits(audienceParams[0])
+			if err != nil {
+				return err
+			}
+			generated := response.Entry.Changes
+			response.Entry, err = utils.ReviewChangelogEntry(response.Entry, utils.ReviewOptions{
+				AIClient:   aiClient,
+				Model:      flags.Model,
+				Tags:       flags.Tags,
+				StyleGuide: flags.StyleGuide,
+				Commits:    commits,
+			})
+			if err != nil {
+				return fmt.Errorf("Error reviewing changelog entry: %v. The changelog file was not changed", err)
+			}
+			if len(response.Entry.Changes) == 0 {
+				return fmt.Errorf("All changes were rejected. The changelog file was not changed")
+			}
+			rejectedCommits = lo.Without(utils.ChangesCommits(generated), utils.ChangesCommits(response.Entry.Changes)...)
+		}
+
+		err = generateVariants(audienceParams[1:], rejectedCommits, flags.Verbose, aiClient, &response, spnr)
+		if err != nil {
+			return err
 		}
+		utils.AssignVariantIDs(&response.Entry, version, flags.IDStrategy, usedIDs)
 
 		if flags.Verbose {
-			spnr.Stop()
 			utils.Eprintf("%s AI Generated changelog entry\n", color.GreenString("\u2713"))
 			utils.Eprintf("\u2192 Tokens used: %d\n", response.InputTokens+response.OutputTokens)
 			utils.Eprintf(" \u2192 Input: %d\n", response.InputTokens)
 			utils.Eprintf(" \u2192 Output: %d\n", response.OutputTokens)
 		}
 
-		jsonOutput, err := json.Marshal(response.Entry)
-		if err != nil {
-			return fmt.Errorf("Error generating JSON: %v", err)
+		generateFollowUps(&response.Entry, utils.FollowUpOptions{
+			AIClient:   aiClient,
+			Model:      flags.Model,
+			StyleGuide: flags.StyleGuide,
+			Language:   flags.Language,
+		}, flags.Migrations, flags.Security, flags.Verbose, spnr)
+
+		if flags.Authors {
+			utils.NewAuthorResolver(flags.AuthorHandles).AttachChangeAuthors(&response.Entry)
+		}
+		if flags.ReferencePatterns != nil {
+			utils.NewReferenceResolver(flags.ReferencePatterns).AttachChangeReferences(&response.Entry)
 		}
 
-		if flags.ExistingChangelog != nil {
+		summaryOptions := utils.SummaryOptions{
+			AIClient:   aiClient,
+			Model:      flags.Model,
+			StyleGuide: flags.StyleGuide,
+			Highlights: flags.Highlights,
+		}
+		if flags.Summary && flags.ExistingChangelogFile == nil {
+			generateSummary(&response.Entry, summaryOptions, flags.Verbose, spnr)
+		}
+
+		// The entry as written to the changelog file is printed, so merged changes and a summary of the merged entry are
+		// part of the output
+		output := response.Entry
+		if flags.ExistingChangelogFile != nil {
+			changelogFile := flags.ExistingChangelogFile
 			if flags.Verbose {
-				if flags.ExistingChangelogInEntries {
-					utils.Eprintf("\u2192 Writing to 'entries' field of changelog file '%s'\n", flags.ExistingChangelogPath)
+				if changelogFile.EntriesKey {
+					utils.Eprintf("\u2192 Writing to 'entries' field of changelog file '%s'\n", changelogFile.Path)
 				} else {
-					utils.Eprintf("\u2192 Writing to changelog file '%s'\n", flags.ExistingChangelogPath)
+					utils.Eprintf("\u2192 Writing to changelog file '%s'\n", changelogFile.Path)
 				}
 			}
 
-			// NOTE: Adding the new entry to the beginning. This is not good for performance but OK for POC.
-			updatedChangelog := append([]models.ChangelogEntry{response.Entry}, flags.ExistingChangelog...)
-			err := utils.WriteChangelogFile(flags.ExistingChangelogPath, flags.ExistingChangelogInEntries, updatedChangelog)
-			if err != nil {
-				return fmt.Errorf("Error writing changelog file '%s': %v", flags.ExistingChangelogPath, err)
+			var updatedChangelog []models.ChangelogEntry
+			var changed bool
+			if flags.Unreleased {
+				var added int
+				updatedChangelog, added = utils.MergeUnreleasedEntry(changelogFile.Entries, response.Entry)
+				changed = true
+				if flags.Verbose {
+					utils.Eprintf("\u2192 Adding %d changes to the %s entry\n", added, utils.UnreleasedVersion)
+				}
+			} else {
+				updatedChangelog, changed, err = utils.UpsertChangelogEntry(changelogFile.Entries, response.Entry, flags.OnConflict)
+				if err != nil {
+					return err
+				}
 			}
-			if flags.Verbose {
-				if flags.ExistingChangelogInEntries {
-					utils.Eprintf("%s Written to 'entries' field of changelog file '%s'\n", color.GreenString("\u2713"), flags.ExistingChangelogPath)
+			entryVersion := lo.Ternary(flags.Unreleased, utils.UnreleasedVersion, version)
+			// The summary is generated after merging so it covers all the changes of the entry
+			if flags.Summary && changed {
+				index := utils.FindChangelogEntry(updatedChangelog, entryVersion)
+				generateSummary(&updatedChangelog[index], summaryOptions, flags.Verbose, spnr)
+			}
+			// Keep the entries sorted by version, e.g. when backfilling an older version
+			updatedChangelog = utils.SortChangelogEntries(updatedChangelog)
+			utils.RefreshContributors(updatedChangelog)
+			if changed {
+				output = updatedChangelog[utils.FindChangelogEntry(updatedChangelog, entryVersion)]
+			}
+			if !changed && flags.OnConflict == utils.ConflictSkip {
+				utils.Eprintf("%s Skipping version %s, it already exists in changelog file '%s'\n", color.YellowString("!"), version, changelogFile.Path)
+			} else if !changed {
+				utils.Eprintf("%s Version %s in changelog file '%s' already has all the generated changes\n", color.YellowString("!"), version, changelogFile.Path)
+			} else {
+				err = utils.WriteChangelogFile(changelogFile, updatedChangelog, utils.WriteChangelogOptions{Backup: flags.Backup})
+				if err != nil {
+					return fmt.Errorf("Error writing changelog file '%s': %v", changelogFile.Path, err)
+				}
+			}
+			if flags.Verbose && changed {
+				if changelogFile.EntriesKey {
+					utils.Eprintf("%s Written to 'entries' field of changelog file '%s'\n", color.GreenString("\u2713"), changelogFile.Path)
 				} else {
-					utils.Eprintf("%s Written to changelog file '%s'\n", color.GreenString("\u2713"), flags.ExistingChangelogPath)
+					utils.Eprintf("%s Written to changelog file '%s'\n", color.GreenString("\u2713"), changelogFile.Path)
 				}
 			}
 		}
 
+		var jsonOutput []byte
 		if flags.Pretty {
-			pretty, err := json.MarshalIndent(response.Entry, "", "  ")
-			if err != nil {
-				return fmt.Errorf("Error pretty printing JSON: %v", err)
-			}
-			fmt.Println(string(pretty))
+			jsonOutput, err = json.MarshalIndent(output, "", "  ")
 		} else {
-			fmt.Println(string(jsonOutput))
+			jsonOutput, err = json.Marshal(output)
 		}
+		if err != nil {
+			return fmt.Errorf("Error generating JSON: %v", err)
+		}
+		fmt.Println(string(jsonOutput))
 		return nil
 	},
 }
 
+// generateFollowUps generates the upgrade guides of the breaking and deprecated changes of the entry and the security
+// advisories of its security changes (if enabled), warning about the changes whose follow-up request failed instead of failing
+func generateFollowUps(entry *models.ChangelogEntry, options utils.FollowUpOptions, migrations bool, security bool, verbose bool, spnr *spinner.Spinner) {
+	followUps := []struct {
+		enabled  bool
+		name     string
+		needs    func(change models.ChangelogChange) bool
+		generate func(entry *models.ChangelogEntry, options utils.FollowUpOptions) utils.FollowUpResult
+	}{
+		{enabled: migrations, name: "upgrade guides", needs: ai.NeedsMigration, generate: utils.GenerateMigrations},
+		{enabled: security, name: "security advisories", needs: ai.NeedsSecurityAdvisory, generate: utils.GenerateSecurityAdvisories},
+	}
+
+	for _, followUp := range followUps {
+		if !followUp.enabled || !lo.SomeBy(entry.Changes, followUp.needs) {
+			continue
+		}
+		if verbose {
+			spnr.Suffix = fmt.Sprintf(" AI Generating %s...", followUp.name)
+			spnr.Start()
+		}
+		result := followUp.generate(entry, options)
+		spnr.Stop()
+		for _, err := range result.Errors {
+			utils.Eprintf("%s %v\n", color.YellowString("!"), err)
+		}
+		if verbose && result.Generated > 0 {
+			utils.Eprintf("%s AI Generated %d %s\n", color.GreenString("\u2713"), result.Generated, followUp.name)
+			utils.Eprintf("\u2192 Tokens used: %d\n", result.InputTokens+result.OutputTokens)
+		}
+	}
+}
+
+// generateSummary generates the summary and highlights of the entry, warning instead of failing if they could not be generated
+func generateSummary(entry *models.ChangelogEntry, options utils.SummaryOptions, verbose bool, spnr *spinner.Spinner) {
+	if verbose {
+		spnr.Suffix = " AI Generating summary..."
+		spnr.Start()
+	}
+	result, err := utils.GenerateSummary(entry, options)
+	spnr.Stop()
+	if err != nil {
+		utils.Eprintf("%s Could not generate the summary of version %s: %v\n", color.YellowString("!"), entry.Version, err)
+		return
+	}
+	if verbose {
+		utils.Eprintf("%s AI Generated summary with %d highlights\n", color.GreenString("\u2713"), len(entry.Highlights))
+		utils.Eprintf("\u2192 Tokens used: %d\n", result.InputTokens+result.OutputTokens)
+	}
+}
+
+// generateVariants generates the changes of the entry for the other audiences, leaving out the rejected commits (the
+// commits of the changes rejected in the review), and repairs their commit references
+func generateVariants(audienceParams []ai.GenerateChangelogEntryParams, rejectedCommits []string, verbose bool, aiClient ai.AIClient, response *ai.GenerateChangelogEntryResponse, spnr *spinner.Spinner) error {
+	for _, params := range audienceParams {
+		params.ExcludeCommits = append(append([]string{}, params.ExcludeCommits...), rejectedCommits...)
+		if verbose {
+			spnr.Suffix = fmt.Sprintf(" AI Generating changelog entry for the %s audience...", params.Audience.Name)
+			spnr.Start()
+		}
+		audienceResponse, err := aiClient.GenerateChangelogEntry(params)
+		spnr.Stop()
+		if err != nil {
+			return fmt.Errorf("Error generating changelog: %v", err)
+		}
+
+		commits, mergeCommits, err := rangeCommits(params)
+		if err != nil {
+			return err
+		}
+		validation := utils.ValidateChangelogCommits(&audienceResponse.Entry, commits, mergeCommits)
+		for _, invalid := range validation.Invalid {
+			utils.Eprintf("%s Removed commit '%s' from change '%s' for the %s audience, it is not in the commit range\n", color.YellowString("!"), invalid.Commit, invalid.Change, params.Audience.Name)
+		}
+
+		if response.Entry.Variants == nil {
+			response.Entry.Variants = map[string][]models.ChangelogChange{}
+		}
+		response.Entry.Variants[params.Audience.Name] = audienceResponse.Entry.Changes
+		response.InputTokens += audienceResponse.InputTokens
+		response.OutputTokens += audienceResponse.OutputTokens
+	}
+	return nil
+}
+
+// rangeCommits returns the commits of the range of the params without its excluded commits, and the merge commits of the range
+func rangeCommits(params ai.GenerateChangelogEntryParams) ([]string, []string, error) {
+	commits, err := git.CommitRange(params.FromCommit, params.ToCommit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error getting commits: %v", err)
+	}
+	commits = lo.Filter(commits, func(commit string, _ int) bool {
+		return commit != "" && !lo.ContainsBy(params.ExcludeCommits, func(excluded string) bool {
+			return git.SameCommit(commit, excluded)
+		})
+	})
+	// Merge commits usually have no changes of their own, so they do not have to be referenced
+	mergeCommits, err := git.MergeCommitRange(params.FromCommit, params.ToCommit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return commits, mergeCommits, nil
+}
+
+// validateGeneratedCommits repairs the commit references of the generated changes and checks that every commit of the
+// range of the params (without its excluded commits) is referenced by a change, failing or asking the model again for the
+// missing commits if onMissingCommits says so
+func validateGeneratedCommits(params ai.GenerateChangelogEntryParams, onMissingCommits utils.MissingCommitsStrategy, verbose bool, aiClient ai.AIClient, response *ai.GenerateChangelogEntryResponse) error {
+	excludeCommits := params.ExcludeCommits
+	commits, mergeCommits, err := rangeCommits(params)
+	if err != nil {
+		return err
+	}
+
+	validation := utils.ValidateChangelogCommits(&response.Entry, commits, mergeCommits)
+
+	if len(validation.Missing) > 0 && onMissingCommits == utils.MissingCommitsRetry {
+		if verbose {
+			utils.Eprintf("\u2192 AI Generating changes for %d commits that are not referenced by any change\n", len(validation.Missing))
+		}
+		params.ExcludeCommits = append(append([]string{}, excludeCommits...), lo.Without(commits, validation.Missing...)...)
+		retry, err := aiClient.GenerateChangelogEntry(params)
+		if err != nil {
+			return fmt.Errorf("Error generating changelog: %v", err)
+		}
+		retryValidation := utils.ValidateChangelogCommits(&retry.Entry, validation.Missing, nil)
+		response.Entry.Changes = append(response.Entry.Changes, retry.Entry.Changes...)
+		response.InputTokens += retry.InputTokens
+		response.OutputTokens += retry.OutputTokens
+
+		validation.Expanded += retryValidation.Expanded
+		validation.Invalid = append(validation.Invalid, retryValidation.Invalid...)
+		validation.NoCommits = append(validation.NoCommits, retryValidation.NoCommits...)
+		validation.Missing = utils.MissingCommits(response.Entry.Changes, commits, mergeCommits)
+	}
+
+	if verbose && validation.Expanded > 0 {
+		utils.Eprintf("\u2192 Expanded %d abbreviated commit hashes\n", validation.Expanded)
+	}
+	for _, invalid := range validation.Invalid {
+		utils.Eprintf("%s Removed commit '%s' from change '%s', it is not in the commit range\n", color.YellowString("!"), invalid.Commit, invalid.Change)
+	}
+	for _, title := range validation.NoCommits {
+		utils.Eprintf("%s Change '%s' does not reference any commit of the range\n", color.YellowString("!"), title)
+	}
+	if len(validation.Missing) == 0 {
+		return nil
+	}
+
+	missing := lo.Map(validation.Missing, func(commit string, _ int) string {
+		return commit[:min(len(commit), 7)]
+	})
+	if onMissingCommits == utils.MissingCommitsError {
+		return fmt.Errorf("%d commits are not referenced by any generated change: %s. The changelog file was not changed", len(missing), strings.Join(missing, ", "))
+	}
+	utils.Eprintf("%s %d commits are not referenced by any generated change: %s\n", color.YellowString("!"), len(missing), strings.Join(missing, ", "))
+	return nil
+}
+
+// printDryRun prints the prompt, schema and model that would be sent to the provider with a token and cost estimate.
+// When the entry is generated for multiple audiences, a list with the dry run of each audience is printed.
+func printDryRun(flags *utils.GenerateFlags, audienceParams []ai.GenerateChangelogEntryParams) error {
+	dryRuns := []*ai.DryRun{}
+	for _, params := range audienceParams {
+		dryRun, err := ai.NewDryRun(flags.Provider, params)
+		if err != nil {
+			return fmt.Errorf("Error building prompt: %v", err)
+		}
+		dryRun.FollowUpRequests = utils.FollowUpRequests(flags)
+		dryRuns = append(dryRuns, dryRun)
+	}
+
+	var value any = dryRuns
+	if len(dryRuns) == 1 {
+		value = dryRuns[0]
+	}
+
+	var output []byte
+	var err error
+	if flags.Pretty {
+		output, err = json.MarshalIndent(value, "", "  ")
+	} else {
+		output, err = json.Marshal(value)
+	}
+	if err != nil {
+		return fmt.Errorf("Error generating JSON: %v", err)
+	}
+
+	if flags.Verbose {
+		utils.Eprintf("\u2192 Dry run, the API is not called\n")
+		for i, dryRun := range dryRuns {
+			if audience := audienceParams[i].Audience.Name; audience != "" {
+				utils.Eprintf("\u2192 Estimated input tokens for the %s audience: %d (~$%.4f with %s)\n", audience, dryRun.EstimatedInputTokens, dryRun.EstimatedInputCost, flags.Model)
+			} else {
+				utils.Eprintf("\u2192 Estimated input tokens: %d (~$%.4f with %s)\n", dryRun.EstimatedInputTokens, dryRun.EstimatedInputCost, flags.Model)
+			}
+		}
+		if requests := utils.FollowUpRequests(flags); len(requests) > 0 {
+			utils.Eprintf("%s Not included in the estimate, follow-up requests: %s\n", color.YellowString("!"), strings.Join(requests, ", "))
+		}
+	}
+
+	if flags.DryRunOutput != "" {
+		err = os.WriteFile(flags.DryRunOutput, append(output, '\n'), 0644)
+		if err != nil {
+			return fmt.Errorf("Error writing file '%s': %v", flags.DryRunOutput, err)
+		}
+		utils.Eprintf("%s Written dry run to '%s'\n", color.GreenString("\u2713"), flags.DryRunOutput)
+		return nil
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(generateCmd)
 
@@ -151,5 +522,22 @@ func init() {
 	generateCmd.Flags().String("apiKey", "", "API key for the LLM provider (can also be set via environment variable, see chlog models for details)")
 	generateCmd.Flags().StringP("date", "d", time.Now().Format("2006-01-02"), "Date for the changelog entry in YYYY-MM-DD format")
 	generateCmd.Flags().Bool("pretty", false, "Prettified JSON output")
-	generateCmd.Flags().String("file", "", "Path to existing changelog JSON file to update with the new entry (should be an array of changelog entries or empty file)")
+	generateCmd.Flags().String("file", "", "Path to existing changelog file (JSON, YAML or TOML, detected by extension) to update with the new entry (should be an array of changelog entries or empty file)")
+	generateCmd.Flags().String("on-conflict", "", "What to do when the version already exists in --file: error, replace, merge (keep the existing changes and add new ones) or skip (default \"error\")")
+	generateCmd.Flags().String("id-strategy", "", "How the IDs of the changes are derived: slug (from the title) or hash (from the version and commits, so they do not change with the wording) (default \"slug\")")
+	generateCmd.Flags().String("on-missing-commits", "", "What to do when commits of the range are not referenced by any generated change: warn, error or retry (ask the model again for the missing commits) (default \"warn\")")
+	generateCmd.Flags().Bool("unreleased", false, "Add the changes of commits that are not in the Unreleased entry of --file yet to the Unreleased entry (release it with chlog release)")
+	generateCmd.Flags().Bool("review", false, "Review each generated change (accept, reject, edit, merge or ask the AI to rewrite it) before writing the entry")
+	generateCmd.Flags().Bool("dry-run", false, "Print the prompt, JSON schema, model and estimated tokens and cost without calling the API")
+	generateCmd.Flags().String("dry-run-output", "", "Write the --dry-run output to a file instead of stdout")
+	generateCmd.Flags().Bool("authors", false, "Add the authors and co-authors (from Co-authored-by trailers) of the commits to each change, and the contributors to the entry")
+	generateCmd.Flags().Bool("summary", false, "Generate a summary of the entry and pick its highlights (the most important changes) with a follow-up request")
+	generateCmd.Flags().Int("highlights", ai.DefaultHighlights, "Maximum number of highlights picked by --summary")
+	generateCmd.Flags().Bool("no-migrations", false, "Skip the follow-up requests that generate an upgrade guide (what changed, before and after snippets and the required actions) for each breaking or deprecated change")
+	generateCmd.Flags().Bool("no-security", false, "Skip the follow-up requests that generate the advisory information (CVE and GHSA IDs from the commit messages, affected component and estimated severity) of each security change")
+	generateCmd.Flags().Bool("references", false, "Add the issues and tickets referenced by the commit messages (e.g. #123, or custom patterns from the config file) to each change and list them in the prompt")
+	generateCmd.Flags().String("audience", "", "Comma separated audiences to write the entry for (end-user, developer, internal or custom audiences from the config file). The first one is used for the changes of the entry and the others are stored as variants")
+	generateCmd.Flags().String("language", "", "Locale code of the language to write the changes in (e.g. de, ja or pt-BR)")
+	generateCmd.Flags().String("prompt-template", "", "Path to a custom prompt template (Go text/template, see the README for the available fields)")
+	generateCmd.Flags().Bool("backup", false, "Keep a copy of the previous changelog file contents at <file>.bak when writing to --file")
 }
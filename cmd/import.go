@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <CHANGELOG.md>",
+	Short: "Import an existing Markdown changelog into the structured format",
+	Long: `Import an existing Keep a Changelog style Markdown changelog into the structured changelog format.
+
+Versions and dates are read from the "## [1.0.0] - 2025-01-01" headings, tags are mapped from the "### Added", "### Fixed", etc. section headings and each bullet item becomes a change. Compare links (e.g. "[1.0.0]: https://github.com/owner/repo/compare/v0.9.0...v1.0.0") are used to set the from and to references.
+
+With --ai, the LLM is used to expand terse descriptions and write the impact statements of the imported changes.
+
+Example:
+	chlog import CHANGELOG.md --file changelog.json --ai`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		err = utils.LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("Error loading config file '%s': %v", configPath, err)
+		}
+
+		verbose, err := utils.GetConfigFlagBool(cmd, "verbose")
+		if err != nil {
+			return err
+		}
+
+		pretty, err := utils.GetConfigFlagBool(cmd, "pretty")
+		if err != nil {
+			return err
+		}
+
+		useAI, err := cmd.Flags().GetBool("ai")
+		if err != nil {
+			return err
+		}
+
+		file, err := utils.ParseFileFlag(cmd, configPath)
+		if err != nil {
+			return err
+		}
+
+		contents, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("Error reading file '%s': %v", args[0], err)
+		}
+
+		tags, err := utils.GetConfigTags()
+		if err != nil {
+			return err
+		}
+
+		imported, err := utils.ParseMarkdownChangelog(string(contents), ai.TagNames(tags))
+		if err != nil {
+			return fmt.Errorf("Error importing '%s': %v", args[0], err)
+		}
+		if len(imported) == 0 {
+			return fmt.Errorf("No versions found in '%s'. Expected \"## [VERSION] - YYYY-MM-DD\" headings", args[0])
+		}
+
+		idStrategyValue, _, err := utils.GetConfigFlagString(cmd, "id-strategy")
+		if err != nil {
+			return err
+		}
+		idStrategy, err := utils.ParseIDStrategy(idStrategyValue)
+		if err != nil {
+			return err
+		}
+		usedIDs := map[string]bool{}
+		for _, entry := range imported {
+			utils.AssignChangeIDs(entry.Changes, entry.Version, idStrategy, usedIDs)
+		}
+
+		if verbose {
+			utils.Eprintf("\u2192 Found %d versions in '%s'\n", len(imported), args[0])
+			for _, entry := range imported {
+				utils.Eprintf(" \u2192 %s (%d changes)\n", color.CyanString(entry.Version), len(entry.Changes))
+			}
+		}
+
+		if useAI {
+			aiFlags, err := utils.ParseAIFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			aiClient, err := ai.NewAIClient(aiFlags.Provider, aiFlags.APIKey)
+			if err != nil {
+				return err
+			}
+
+			if verbose {
+				utils.Eprintf("\u2192 Using AI provider: %s\n", color.MagentaString("%s (model: %s)", aiFlags.Provider, aiFlags.Model))
+			}
+
+			for i, entry := range imported {
+				if len(entry.Changes) == 0 {
+					continue
+				}
+
+				spnr := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+				spnr.Writer = os.Stderr
+				if verbose {
+					spnr.Suffix = fmt.Sprintf(" AI Completing changelog entry %s...", entry.Version)
+					spnr.Start()
+				}
+
+				imported[i], err = completeImportedEntry(aiClient, aiFlags.Model, entry)
+				spnr.Stop()
+				if err != nil {
+					return fmt.Errorf("Error completing changelog entry '%s': %v", entry.Version, err)
+				}
+
+				if verbose {
+					utils.Eprintf("%s AI Completed changelog entry %s\n", color.GreenString("\u2713"), color.CyanString(entry.Version))
+				}
+			}
+		}
+
+		if file != "" {
+			backup, err := utils.GetConfigFlagBool(cmd, "backup")
+			if err != nil {
+				return err
+			}
+
+			changelogFile, err := utils.LockChangelogFile(file)
+			if err != nil {
+				return err
+			}
+			defer changelogFile.Unlock()
+
+			existingVersions := lo.Map(changelogFile.Entries, func(entry models.ChangelogEntry, _ int) string {
+				return entry.Version
+			})
+			newEntries := lo.Filter(imported, func(entry models.ChangelogEntry, _ int) bool {
+				if lo.Contains(existingVersions, entry.Version) {
+					utils.Eprintf("%s Skipping version %s, it already exists in '%s'\n", color.YellowString("!"), entry.Version, file)
+					return false
+				}
+				return true
+			})
+
+			// Change IDs must also be unique across the existing entries
+			usedIDs = utils.ChangelogChangeIDs(changelogFile.Entries, "")
+			for _, entry := range newEntries {
+				utils.AssignChangeIDs(entry.Changes, entry.Version, idStrategy, usedIDs)
+			}
+
+			// Imported versions are usually older history, so they are added after the existing entries before sorting by version
+			updatedChangelog := utils.SortChangelogEntries(append(changelogFile.Entries, newEntries...))
+			err = utils.WriteChangelogFile(changelogFile, updatedChangelog, utils.WriteChangelogOptions{Backup: backup})
+			if err != nil {
+				return fmt.Errorf("Error writing changelog file '%s': %v", file, err)
+			}
+			if verbose {
+				utils.Eprintf("%s Imported %d versions to changelog file '%s'\n", color.GreenString("\u2713"), len(newEntries), file)
+			}
+		}
+
+		var output []byte
+		if pretty {
+			output, err = json.MarshalIndent(imported, "", "  ")
+		} else {
+			output, err = json.Marshal(imported)
+		}
+		if err != nil {
+			return fmt.Errorf("Error generating JSON: %v", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	},
+}
+
+// completeImportedEntry uses the LLM to fill the descriptions and impacts of an imported entry.
+// Only the description, impact and importance of each change are taken from the response.
+func completeImportedEntry(aiClient ai.AIClient, model string, entry models.ChangelogEntry) (models.ChangelogEntry, error) {
+	prompt, err := ai.BuildImportPrompt(entry, utils.GetConfigStyleGuide())
+	if err != nil {
+		return entry, err
+	}
+
+	response, err := aiClient.GenerateChangelogEntryFromPrompt(model, prompt, nil)
+	if err != nil {
+		return entry, err
+	}
+
+	if len(response.Entry.Changes) != len(entry.Changes) {
+		return entry, fmt.Errorf("expected %d changes in the AI response, got %d", len(entry.Changes), len(response.Entry.Changes))
+	}
+
+	for i, change := range response.Entry.Changes {
+		if change.Description != "" {
+			entry.Changes[i].Description = change.Description
+		}
+		entry.Changes[i].Impact = change.Impact
+		entry.Changes[i].Importance = change.Importance
+	}
+	return entry, nil
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringP("config", "c", "", "Path to config file (optional, chlog.yaml will be loaded if present in the current directory)")
+	importCmd.Flags().String("file", "", "Path to changelog file (JSON, YAML or TOML) to add the imported entries to. Versions that already exist in the file are skipped")
+	importCmd.Flags().Bool("backup", false, "Keep a copy of the previous changelog file contents at <file>.bak when writing to --file")
+	importCmd.Flags().Bool("ai", false, "Use the LLM to expand terse descriptions and write impact statements")
+	importCmd.Flags().String("id-strategy", "", "How the IDs of the changes are derived: slug (from the title) or hash (from the version and commits) (default \"slug\")")
+	importCmd.Flags().StringP("provider", "p", "openai", "LLM provider used with --ai (see chlog models for available options)")
+	importCmd.Flags().StringP("model", "m", "", "LLM model used with --ai (see chlog models for available options and defaults)")
+	importCmd.Flags().String("apiKey", "", "API key for the LLM provider (can also be set via environment variable, see chlog models for details)")
+	importCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	importCmd.Flags().Bool("pretty", false, "Prettified JSON output")
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,13 +14,6 @@ import (
 	"github.com/spf13/cobra"
 )
 
-type ChangelogFile struct {
-	Title       string                  `json:"title"`
-	Description string                  `json:"description"`
-	Repository  string                  `json:"repository"`
-	Entries     []models.ChangelogEntry `json:"entries"`
-}
-
 type ChlogConfig struct {
 	Provider string `yaml:"provider"`
 	Model    string `yaml:"model"`
@@ -45,7 +37,7 @@ You can also optionally create a configuration file for the chlog generate comma
 		utils.Eprintln("")
 		utils.Eprintln("Press Ctrl+C to cancel at any time.")
 
-		fileName, err := utils.Prompt("changelog file name", "changelog.json")
+		fileName, err := utils.Prompt("changelog file name (.json, .yaml or .toml)", "changelog.json")
 		if err != nil {
 			return err
 		}
@@ -71,15 +63,15 @@ You can also optionally create a configuration file for the chlog generate comma
 			return err
 		}
 
-		file := &ChangelogFile{
+		file := &models.ChangelogFile{
 			Title:       title,
 			Description: description,
 			Repository:  repoUrl,
 			Entries:     []models.ChangelogEntry{},
 		}
-		fileContent, err := json.MarshalIndent(file, "", "  ")
+		fileContent, err := utils.MarshalChangelogFile(fileName, file)
 		if err != nil {
-			return fmt.Errorf("Failed to marshal JSON: %v", err)
+			return fmt.Errorf("Failed to marshal changelog file: %v", err)
 		}
 
 		err = os.WriteFile(fileName, fileContent, 0644)
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [FILE]",
+	Short: "Validate a changelog file",
+	Long: `Validate a changelog file against the changelog file JSON schema and check that:
+  - versions are unique and ordered from the most recent to the oldest
+  - dates are in YYYY-MM-DD format
+  - change IDs are unique within an entry
+  - every change references at least one commit and the commits exist in the repository
+  - tags are one of the allowed tags (the 'tags' key of the config file, or the default tags)
+
+The file defaults to the 'file' key of the config file. Exits with a non-zero status code if any errors are found, so it can be used in CI.
+
+Example:
+	chlog lint changelog.json --format sarif > chlog.sarif`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printSchema, err := cmd.Flags().GetBool("print-schema")
+		if err != nil {
+			return err
+		}
+		if printSchema {
+			schema, err := json.MarshalIndent(models.ChangelogFileSchema, "", "  ")
+			if err != nil {
+				return fmt.Errorf("Error generating JSON schema: %v", err)
+			}
+			fmt.Println(string(schema))
+			return nil
+		}
+
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		err = utils.LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("Error loading config file '%s': %v", configPath, err)
+		}
+
+		var file string
+		if len(args) > 0 {
+			file = args[0]
+		} else {
+			file, err = utils.ParseFileFlag(cmd, configPath)
+			if err != nil {
+				return err
+			}
+		}
+		if file == "" {
+			return fmt.Errorf("No changelog file specified. Pass it as an argument or set the 'file' key in the config file")
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if format != "text" && format != "json" && format != "sarif" {
+			return fmt.Errorf("Invalid format '%s'. Supported formats are: text, json, sarif", format)
+		}
+
+		noGit, err := cmd.Flags().GetBool("no-git")
+		if err != nil {
+			return err
+		}
+
+		tags, err := utils.GetConfigTags()
+		if err != nil {
+			return err
+		}
+
+		checkCommits := !noGit
+		if checkCommits && (git.IsInstalled() != nil || git.IsRepository() != nil) {
+			utils.Eprintf("%s Not inside a Git repository, skipping commit checks\n", color.YellowString("!"))
+			checkCommits = false
+		}
+
+		diagnostics, err := utils.LintChangelogFile(file, utils.LintOptions{
+			AllowedTags:  ai.TagNames(tags),
+			CheckCommits: checkCommits,
+		})
+		if err != nil {
+			return err
+		}
+		errors, warnings := utils.CountLintDiagnostics(diagnostics)
+
+		switch format {
+		case "json":
+			output, err := json.MarshalIndent(map[string]any{
+				"file":        file,
+				"errors":      errors,
+				"warnings":    warnings,
+				"diagnostics": diagnostics,
+			}, "", "  ")
+			if err != nil {
+				return fmt.Errorf("Error generating JSON: %v", err)
+			}
+			fmt.Println(string(output))
+		case "sarif":
+			output, err := utils.FormatLintSARIF(file, diagnostics)
+			if err != nil {
+				return fmt.Errorf("Error generating SARIF: %v", err)
+			}
+			fmt.Println(string(output))
+		default:
+			for _, diagnostic := range diagnostics {
+				location := file
+				if diagnostic.Line > 0 {
+					location = fmt.Sprintf("%s:%d", file, diagnostic.Line)
+				}
+				severity := color.RedString(string(diagnostic.Severity))
+				if diagnostic.Severity == utils.LintWarning {
+					severity = color.YellowString(string(diagnostic.Severity))
+				}
+				fmt.Printf("%s %s %s %s\n", location, severity, diagnostic.Message, color.New(color.Faint).Sprintf("(%s at %s)", diagnostic.Rule, diagnostic.Path))
+			}
+			if len(diagnostics) == 0 {
+				utils.Eprintf("%s No problems found in '%s'\n", color.GreenString("\u2713"), file)
+			} else {
+				utils.Eprintf("\n%d errors, %d warnings\n", errors, warnings)
+			}
+		}
+
+		if errors > 0 {
+			return fmt.Errorf("Found %d errors in changelog file '%s'", errors, file)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringP("config", "c", "", "Path to config file (optional, chlog.yaml will be loaded if present in the current directory)")
+	lintCmd.Flags().String("file", "", "Path to changelog file to lint (alternative to the FILE argument, can also be set in the config file)")
+	lintCmd.Flags().String("format", "text", "Output format (text, json or sarif)")
+	lintCmd.Flags().Bool("no-git", false, "Skip checking that commits exist in the Git repository")
+	lintCmd.Flags().Bool("print-schema", false, "Print the changelog file JSON schema and exit")
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// regenerateCmd represents the regenerate command
+var regenerateCmd = &cobra.Command{
+	Use:   "regenerate <VERSION>",
+	Short: "Regenerate an entry or a single change of the changelog file with feedback",
+	Long: `Regenerate the entry of the specified version, or a single change of it with --change, and replace it in the changelog file.
+
+The prompt is rebuilt from the commits of the entry (between its from_ref and to_ref) or the commits of the change, and includes the previous output and your feedback. The IDs of regenerated changes are kept, and their commit references are validated like the ones of chlog generate. Regenerating the whole entry removes its audience variants and translations, which describe the previous changes.
+
+Example:
+	chlog regenerate 1.2.0 --change add-new-feature --feedback "mention the new --dry-run flag" --file changelog.json`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version := args[0]
+
+		err := git.IsInstalled()
+		if err != nil {
+			return err
+		}
+
+		flags, err := utils.ParseEditFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer flags.ChangelogFile.Unlock()
+
+		aiFlags, err := utils.ParseAIFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		verbose, err := utils.GetConfigFlagBool(cmd, "verbose")
+		if err != nil {
+			return err
+		}
+
+		pretty, err := utils.GetConfigFlagBool(cmd, "pretty")
+		if err != nil {
+			return err
+		}
+
+		changeID, err := cmd.Flags().GetString("change")
+		if err != nil {
+			return err
+		}
+
+		feedback, err := cmd.Flags().GetString("feedback")
+		if err != nil {
+			return err
+		}
+
+		onMissingCommitsValue, _, err := utils.GetConfigFlagString(cmd, "on-missing-commits")
+		if err != nil {
+			return err
+		}
+		onMissingCommits, err := utils.ParseMissingCommitsStrategy(onMissingCommitsValue)
+		if err != nil {
+			return err
+		}
+
+		entries := flags.ChangelogFile.Entries
+		entryIndex := utils.FindChangelogEntry(entries, version)
+		if entryIndex == -1 {
+			return fmt.Errorf("Version '%s' not found in the changelog file", version)
+		}
+		entry := entries[entryIndex]
+
+		if changeID == "" && (entry.FromRef == "" || entry.ToRef == "") {
+			return fmt.Errorf("Version '%s' has no from and to references to regenerate it from. Use '--change' to regenerate a single change from its commits", version)
+		}
+		if changeID != "" {
+			changeIndex := utils.FindChangelogChange(entry, changeID)
+			if changeIndex == -1 {
+				return fmt.Errorf("Change '%s' not found in version '%s'", changeID, version)
+			}
+			if len(entry.Changes[changeIndex].Commits) == 0 {
+				return fmt.Errorf("Change '%s' has no commits to regenerate it from", changeID)
+			}
+		}
+
+		prompt, err := ai.BuildRegeneratePrompt(ai.RegenerateParams{
+			Entry:      entry,
+			ChangeID:   changeID,
+			Feedback:   feedback,
+			Tags:       flags.Tags,
+			StyleGuide: utils.GetConfigStyleGuide(),
+			Language:   entry.Language,
+		})
+		if err != nil {
+			return fmt.Errorf("Error building prompt: %v", err)
+		}
+
+		aiClient, err := ai.NewAIClient(aiFlags.Provider, aiFlags.APIKey)
+		if err != nil {
+			return err
+		}
+
+		spnr := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		spnr.Writer = os.Stderr
+		if verbose {
+			utils.Eprintf("\u2192 Using AI provider: %s\n", color.MagentaString("%s (model: %s)", aiFlags.Provider, aiFlags.Model))
+			spnr.Suffix = fmt.Sprintf(" AI Regenerating changelog entry %s...", version)
+			spnr.Start()
+		}
+
+		response, err := aiClient.GenerateChangelogEntryFromPrompt(aiFlags.Model, prompt, ai.TagNames(flags.Tags))
+		spnr.Stop()
+		if err != nil {
+			return fmt.Errorf("Error regenerating changelog: %v", err)
+		}
+
+		if verbose {
+			utils.Eprintf("%s AI Regenerated changelog entry %s\n", color.GreenString("\u2713"), color.CyanString(version))
+			utils.Eprintf("\u2192 Tokens used: %d\n", response.InputTokens+response.OutputTokens)
+		}
+
+		// Upgrade guides and security advisories are regenerated too, unless they are turned off in the config file
+		migrations, security := utils.GetConfigMigrations(), utils.GetConfigSecurity()
+		followUpOptions := utils.FollowUpOptions{
+			AIClient:   aiClient,
+			Model:      aiFlags.Model,
+			StyleGuide: utils.GetConfigStyleGuide(),
+			Language:   entry.Language,
+		}
+
+		// Entries with contributors were generated with --authors, so the regenerated changes get their authors too
+		var authors *utils.AuthorResolver
+		if entry.Contributors != nil {
+			handles, err := utils.GetConfigAuthorHandles()
+			if err != nil {
+				return err
+			}
+			authors = utils.NewAuthorResolver(handles)
+		}
+		// The same goes for entries with references, which were generated with --references
+		var references *utils.ReferenceResolver
+		if utils.HasReferences(entry) {
+			patterns, err := utils.GetConfigReferencePatterns(utils.ChangelogRepositoryInfo(flags.ChangelogFile).URL)
+			if err != nil {
+				return err
+			}
+			references = utils.NewReferenceResolver(patterns)
+		}
+
+		var updated []models.ChangelogEntry
+		var output any
+		if changeID != "" {
+			if len(response.Entry.Changes) != 1 {
+				return fmt.Errorf("Expected 1 change in the AI response, got %d", len(response.Entry.Changes))
+			}
+			previous := entry.Changes[utils.FindChangelogChange(entry, changeID)]
+			// The change is regenerated from the commits of the previous change, so it keeps covering all of them
+			validation := utils.ValidateChangelogCommits(&response.Entry, previous.Commits, nil)
+			for _, invalid := range validation.Invalid {
+				utils.Eprintf("%s Removed commit '%s' from change '%s', it is not one of the commits of the change\n", color.YellowString("!"), invalid.Commit, invalid.Change)
+			}
+			change := response.Entry.Changes[0]
+			change.ID = previous.ID
+			change.Extra = previous.Extra
+			change.Commits = append(change.Commits, validation.Missing...)
+			followUpEntry := models.ChangelogEntry{Changes: []models.ChangelogChange{change}}
+			generateFollowUps(&followUpEntry, followUpOptions, migrations, security, verbose, spnr)
+			change = followUpEntry.Changes[0]
+			if authors != nil {
+				authorsEntry := models.ChangelogEntry{Changes: []models.ChangelogChange{change}}
+				authors.AttachChangeAuthors(&authorsEntry)
+				change = authorsEntry.Changes[0]
+			}
+			if references != nil {
+				referencesEntry := models.ChangelogEntry{Changes: []models.ChangelogChange{change}}
+				references.AttachChangeReferences(&referencesEntry)
+				change = referencesEntry.Changes[0]
+			}
+
+			updated, err = utils.ReplaceChangelogChange(entries, version, changeID, change, ai.TagNames(flags.Tags))
+			if err != nil {
+				return fmt.Errorf("Invalid regenerated change: %v", err)
+			}
+			output = change
+		} else {
+			err = validateGeneratedCommits(ai.GenerateChangelogEntryParams{
+				FromCommit: entry.FromRef,
+				ToCommit:   entry.ToRef,
+				Model:      aiFlags.Model,
+				Version:    version,
+				Date:       entry.Date,
+				Tags:       flags.Tags,
+				StyleGuide: utils.GetConfigStyleGuide(),
+				Language:   entry.Language,
+			}, onMissingCommits, verbose, aiClient, &response)
+			if err != nil {
+				return err
+			}
+
+			regenerated := entry
+			regenerated.Changes = response.Entry.Changes
+			// The variants, translations, summary and highlights describe the previous changes. The summary and
+			// highlights are regenerated below, the others have to be generated again
+			regenerated.Variants = nil
+			regenerated.Translations = nil
+			regenerated.SummaryTranslations = nil
+			regenerated.Summary = ""
+			regenerated.Highlights = nil
+			if len(entry.Variants) > 0 || len(entry.Translations) > 0 {
+				utils.Eprintf("%s Removed the audience variants and translations of version %s, they describe the previous changes. Generate them again with 'chlog generate --audience' and 'chlog translate'\n", color.YellowString("!"), version)
+			}
+			usedIDs := utils.ChangelogChangeIDs(entries, version)
+			for i, change := range regenerated.Changes {
+				// Keep the IDs of changes that are about the same commits as before
+				if previous := utils.FindChangelogChangeByCommits(entry, change.Commits); previous != nil && !usedIDs[previous.ID] {
+					regenerated.Changes[i].ID = utils.UniqueID(previous.ID, usedIDs)
+					regenerated.Changes[i].Extra = previous.Extra
+					continue
+				}
+				regenerated.Changes[i].ID = utils.UniqueID(utils.ChangeID(flags.IDStrategy, version, change), usedIDs)
+			}
+
+			generateFollowUps(&regenerated, followUpOptions, migrations, security, verbose, spnr)
+			if authors != nil {
+				authors.AttachChangeAuthors(&regenerated)
+			}
+			if references != nil {
+				references.AttachChangeReferences(&regenerated)
+			}
+
+			updated = append([]models.ChangelogEntry{}, entries...)
+			updated[entryIndex] = regenerated
+			output = regenerated
+		}
+
+		// Entries with a summary were generated with --summary, so it is regenerated to match the changes
+		if entry.Summary != "" {
+			generateSummary(&updated[entryIndex], utils.SummaryOptions{
+				AIClient:   aiClient,
+				Model:      aiFlags.Model,
+				StyleGuide: utils.GetConfigStyleGuide(),
+				Highlights: utils.GetConfigHighlights(),
+			}, verbose, spnr)
+			if changeID == "" {
+				output = updated[entryIndex]
+			}
+		}
+
+		err = writeEditedChangelog(flags, updated)
+		if err != nil {
+			return err
+		}
+		if verbose {
+			utils.Eprintf("%s Written to changelog file '%s'\n", color.GreenString("\u2713"), flags.ChangelogFile.Path)
+		}
+
+		var jsonOutput []byte
+		if pretty {
+			jsonOutput, err = json.MarshalIndent(output, "", "  ")
+		} else {
+			jsonOutput, err = json.Marshal(output)
+		}
+		if err != nil {
+			return fmt.Errorf("Error generating JSON: %v", err)
+		}
+		fmt.Println(string(jsonOutput))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(regenerateCmd)
+
+	addEditFlags(regenerateCmd)
+	regenerateCmd.Flags().String("change", "", "ID of the change to regenerate (default: the whole entry)")
+	regenerateCmd.Flags().String("feedback", "", "Feedback for the LLM on what to change (e.g. \"mention the new flag\")")
+	regenerateCmd.Flags().String("on-missing-commits", "", "What to do when commits of the entry are not referenced by any regenerated change: warn, error or retry (default \"warn\")")
+	regenerateCmd.Flags().StringP("provider", "p", "openai", "LLM provider (see chlog models for available options)")
+	regenerateCmd.Flags().StringP("model", "m", "", "LLM model (see chlog models for available options and defaults)")
+	regenerateCmd.Flags().String("apiKey", "", "API key for the LLM provider (can also be set via environment variable, see chlog models for details)")
+	regenerateCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	regenerateCmd.Flags().Bool("pretty", false, "Prettified JSON output")
+}
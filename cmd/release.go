@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// releaseCmd represents the release command
+var releaseCmd = &cobra.Command{
+	Use:   "release <VERSION>",
+	Short: "Release the Unreleased entry of the changelog file as the specified version",
+	Long: `Release the Unreleased entry of the changelog file as the specified version.
+
+The Unreleased entry (maintained with chlog generate --unreleased) is renamed to the version, its date and to reference are set, and a new empty Unreleased entry is started from the released reference.
+
+The to reference defaults to the Git tag of the version ("<VERSION>" or "v<VERSION>") if it exists, and to the current commit otherwise.
+
+Example:
+	chlog release 1.2.0 --file changelog.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version := args[0]
+
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		err = utils.LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("Error loading config file '%s': %v", configPath, err)
+		}
+
+		file, err := utils.ParseFileFlag(cmd, configPath)
+		if err != nil {
+			return err
+		}
+		if file == "" {
+			return fmt.Errorf("No changelog file specified. Use the '--file' flag or the 'file' key of the config file")
+		}
+
+		date, err := cmd.Flags().GetString("date")
+		if err != nil {
+			return err
+		}
+
+		_, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			return fmt.Errorf("Invalid date format '%s'. Use YYYY-MM-DD format", date)
+		}
+
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+		if to == "" {
+			to = releaseRef(version)
+		}
+
+		backup, err := utils.GetConfigFlagBool(cmd, "backup")
+		if err != nil {
+			return err
+		}
+
+		changelogFile, err := utils.LockChangelogFile(file)
+		if err != nil {
+			return err
+		}
+		defer changelogFile.Unlock()
+
+		updated, err := utils.ReleaseUnreleasedEntry(changelogFile.Entries, version, date, to)
+		if err != nil {
+			return err
+		}
+
+		err = utils.WriteChangelogFile(changelogFile, utils.SortChangelogEntries(updated), utils.WriteChangelogOptions{Backup: backup})
+		if err != nil {
+			return fmt.Errorf("Error writing changelog file '%s': %v", file, err)
+		}
+
+		utils.Eprintf("%s Released %s as %s in changelog file '%s'\n", color.GreenString("\u2713"), utils.UnreleasedVersion, color.CyanString(version), file)
+		return nil
+	},
+}
+
+// releaseRef returns the Git tag of the version if it exists, or the current commit
+func releaseRef(version string) string {
+	if git.IsInstalled() != nil {
+		return ""
+	}
+
+	for _, tag := range []string{version, "v" + version} {
+		if git.IsValidRef("refs/tags/"+tag) == nil {
+			return tag
+		}
+	}
+
+	hash, err := git.ShortHash("HEAD")
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+
+	releaseCmd.Flags().StringP("config", "c", "", "Path to config file (optional, chlog.yaml will be loaded if present in the current directory)")
+	releaseCmd.Flags().String("file", "", "Path to changelog file (JSON, YAML or TOML) with the Unreleased entry")
+	releaseCmd.Flags().StringP("date", "d", time.Now().Format("2006-01-02"), "Release date in YYYY-MM-DD format")
+	releaseCmd.Flags().StringP("to", "t", "", "Ending commit reference of the release (default: the version's Git tag if it exists, otherwise the current commit)")
+	releaseCmd.Flags().Bool("backup", false, "Keep a copy of the previous changelog file contents at <file>.bak")
+}
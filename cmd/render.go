@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render [FILE]",
+	Short: "Render a changelog file as Markdown",
+	Long: `Render a changelog file as a Keep a Changelog style Markdown changelog, with a section per tag.
+
+The file defaults to the 'file' key of the config file. The title and description of the changelog file are used for the heading.
+
+With --audience, the changes written for that audience (see chlog generate --audience) are rendered when the entry has them. Changes marked as internal are only rendered for the internal audience.
+
+With --min-importance, less important changes (e.g. trivial refactors) are left out of the output while remaining in the file. Changes without an importance are always rendered.
+
+Example:
+	chlog render changelog.json --output CHANGELOG.md
+	chlog render --audience developer --version 1.2.0`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		err = utils.LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("Error loading config file '%s': %v", configPath, err)
+		}
+
+		var file string
+		if len(args) > 0 {
+			file = args[0]
+		} else {
+			file, err = utils.ParseFileFlag(cmd, configPath)
+			if err != nil {
+				return err
+			}
+		}
+		if file == "" {
+			return fmt.Errorf("No changelog file specified. Pass it as an argument or set the 'file' key in the config file")
+		}
+
+		audience, err := cmd.Flags().GetString("audience")
+		if err != nil {
+			return err
+		}
+		if audience != "" {
+			audiences, err := utils.GetConfigAudiences()
+			if err != nil {
+				return err
+			}
+			found, ok := ai.FindAudience(audiences, audience)
+			if !ok {
+				return fmt.Errorf("Invalid audience '%s'. Available audiences are: %s", audience, strings.Join(ai.AudienceNames(audiences), ", "))
+			}
+			audience = found.Name
+		}
+
+		language, err := cmd.Flags().GetString("language")
+		if err != nil {
+			return err
+		}
+
+		version, err := cmd.Flags().GetString("version")
+		if err != nil {
+			return err
+		}
+
+		minImportanceValue, _, err := utils.GetConfigFlagString(cmd, "min-importance")
+		if err != nil {
+			return err
+		}
+		minImportance, err := utils.ParseImportance(minImportanceValue)
+		if err != nil {
+			return err
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		tags, err := utils.GetConfigTags()
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("Error reading changelog file '%s': %v", file, err)
+		}
+
+		changelogFile, err := utils.ParseAndValidateChangelogFile(file)
+		if err != nil {
+			return err
+		}
+
+		entries := changelogFile.Entries
+		if version != "" {
+			index := utils.FindChangelogEntry(entries, version)
+			if index == -1 {
+				return fmt.Errorf("Version '%s' not found in the changelog file", version)
+			}
+			entries = []models.ChangelogEntry{entries[index]}
+		}
+
+		repository := utils.ChangelogRepositoryInfo(changelogFile)
+		markdown := utils.RenderMarkdown(entries, utils.RenderOptions{
+			Title:         repository.Title,
+			Description:   repository.Description,
+			Audience:      audience,
+			Language:      language,
+			Tags:          tags,
+			MinImportance: minImportance,
+		})
+
+		if output == "" {
+			fmt.Print(markdown)
+			return nil
+		}
+
+		err = os.WriteFile(output, []byte(markdown), 0644)
+		if err != nil {
+			return fmt.Errorf("Error writing file '%s': %v", output, err)
+		}
+		utils.Eprintf("%s Rendered changelog file '%s' to '%s'\n", color.GreenString("\u2713"), file, output)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+
+	renderCmd.Flags().StringP("config", "c", "", "Path to config file (optional, chlog.yaml will be loaded if present in the current directory)")
+	renderCmd.Flags().String("file", "", "Path to changelog file (JSON, YAML or TOML), used if FILE is not specified")
+	renderCmd.Flags().String("audience", "", "Render the changes written for this audience (end-user, developer, internal or a custom audience). Internal changes are only rendered for the internal audience")
+	renderCmd.Flags().String("language", "", "Render the translations for this locale from the 'translations' and 'summary_translations' fields of the entries (see chlog translate --mode field)")
+	renderCmd.Flags().String("min-importance", "", "Only render changes that are at least this important: trivial, minor or major (changes without an importance are always rendered)")
+	renderCmd.Flags().String("version", "", "Only render this version")
+	renderCmd.Flags().StringP("output", "o", "", "Write the Markdown to a file instead of stdout")
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// sortCmd represents the sort command
+var sortCmd = &cobra.Command{
+	Use:   "sort [FILE]",
+	Short: "Sort the entries of a changelog file by version",
+	Long: `Sort the entries of a changelog file from the most recent to the oldest version.
+
+Semantic versions are sorted by precedence (including pre-release versions, e.g. 1.0.0-alpha < 1.0.0-beta < 1.0.0) and date-based versions (YYYY-MM-DD) by date. The Unreleased entry is always first, followed by the semantic versions, then the date-based versions and then any other versions, which are sorted by their dates.
+
+The file defaults to the 'file' key of the config file. With --check, the file is not changed and the command exits with a non-zero status code if it is not sorted.
+
+Example:
+	chlog sort changelog.json`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		err = utils.LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("Error loading config file '%s': %v", configPath, err)
+		}
+
+		var file string
+		if len(args) > 0 {
+			file = args[0]
+		} else {
+			file, err = utils.ParseFileFlag(cmd, configPath)
+			if err != nil {
+				return err
+			}
+		}
+		if file == "" {
+			return fmt.Errorf("No changelog file specified. Pass it as an argument or set the 'file' key in the config file")
+		}
+
+		check, err := cmd.Flags().GetBool("check")
+		if err != nil {
+			return err
+		}
+
+		backup, err := utils.GetConfigFlagBool(cmd, "backup")
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("Error reading changelog file '%s': %v", file, err)
+		}
+
+		changelogFile, err := utils.LockChangelogFile(file)
+		if err != nil {
+			return err
+		}
+		defer changelogFile.Unlock()
+
+		sorted := utils.SortChangelogEntries(changelogFile.Entries)
+		if reflect.DeepEqual(sorted, changelogFile.Entries) {
+			utils.Eprintf("%s Changelog file '%s' is already sorted\n", color.GreenString("\u2713"), file)
+			return nil
+		}
+
+		if check {
+			return fmt.Errorf("Changelog file '%s' is not sorted by version. Run 'chlog sort %s' to sort it", file, file)
+		}
+
+		err = utils.WriteChangelogFile(changelogFile, sorted, utils.WriteChangelogOptions{Backup: backup})
+		if err != nil {
+			return fmt.Errorf("Error writing changelog file '%s': %v", file, err)
+		}
+
+		utils.Eprintf("%s Sorted changelog file '%s'\n", color.GreenString("\u2713"), file)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sortCmd)
+
+	sortCmd.Flags().StringP("config", "c", "", "Path to config file (optional, chlog.yaml will be loaded if present in the current directory)")
+	sortCmd.Flags().String("file", "", "Path to changelog file (JSON, YAML or TOML), used if FILE is not specified")
+	sortCmd.Flags().Bool("check", false, "Check that the file is sorted without changing it")
+	sortCmd.Flags().Bool("backup", false, "Keep a copy of the previous changelog file contents at <file>.bak")
+}
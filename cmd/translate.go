@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/ammar-ahmed22/chlog/utils"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// translateCmd represents the translate command
+var translateCmd = &cobra.Command{
+	Use:   "translate --to <LOCALES>",
+	Short: "Translate the entries of the changelog file to other languages",
+	Long: `Translate the summary of each entry in the changelog file and the title, description, impact, upgrade guide and security summary of its changes to other languages.
+
+The IDs, commits, tags, versions and upgrade guide snippets are kept unchanged. By default, the translations are written to a changelog file per locale next to the changelog file (e.g. changelog.de.json). With --mode field, they are stored in the 'translations' and 'summary_translations' fields of each entry instead.
+
+Each translation stores a hash of the text it was translated from. Entries that are already translated from their current text are skipped, and entries that were edited since they were translated are translated again, so the command can be run after each release.
+
+Example:
+	chlog translate --to de,ja --file changelog.json
+	chlog translate --to fr --mode field`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags, err := utils.ParseEditFlags(cmd)
+		if err != nil {
+			return err
+		}
+		defer flags.ChangelogFile.Unlock()
+
+		locales, err := cmd.Flags().GetStringSlice("to")
+		if err != nil {
+			return err
+		}
+		if len(locales) == 0 {
+			return fmt.Errorf("No locales specified. Use '--to' to specify them (e.g. --to de,ja)")
+		}
+		for _, locale := range locales {
+			if err := ai.ValidateLanguage(locale); err != nil {
+				return fmt.Errorf("Invalid locale '%s'. Use a locale code (e.g. de, ja or pt-BR)", locale)
+			}
+		}
+
+		modeValue, err := cmd.Flags().GetString("mode")
+		if err != nil {
+			return err
+		}
+		mode, err := utils.ParseTranslationMode(modeValue)
+		if err != nil {
+			return err
+		}
+
+		verbose, err := utils.GetConfigFlagBool(cmd, "verbose")
+		if err != nil {
+			return err
+		}
+
+		aiFlags, err := utils.ParseAIFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		aiClient, err := ai.NewAIClient(aiFlags.Provider, aiFlags.APIKey)
+		if err != nil {
+			return err
+		}
+
+		if verbose {
+			utils.Eprintf("\u2192 Using AI provider: %s\n", color.MagentaString("%s (model: %s)", aiFlags.Provider, aiFlags.Model))
+		}
+
+		styleGuide := utils.GetConfigStyleGuide()
+		translate := func(entry models.ChangelogEntry, locale string) (models.ChangelogEntry, error) {
+			spnr := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+			spnr.Writer = os.Stderr
+			if verbose {
+				spnr.Suffix = fmt.Sprintf(" AI Translating changelog entry %s to %s...", entry.Version, ai.LanguageName(locale))
+				spnr.Start()
+			}
+			defer spnr.Stop()
+
+			prompt, err := ai.BuildTranslatePrompt(entry, locale, styleGuide)
+			if err != nil {
+				return entry, err
+			}
+			response, err := aiClient.GenerateTranslation(aiFlags.Model, prompt)
+			if err != nil {
+				return entry, err
+			}
+			return utils.TranslatedChangelogEntry(entry, response.Translation, locale)
+		}
+
+		entries := append([]models.ChangelogEntry{}, flags.ChangelogFile.Entries...)
+		for _, locale := range locales {
+			var localeFile *utils.ParsedChangelogFile
+			var localeEntries []models.ChangelogEntry
+			if mode == utils.TranslationFiles {
+				localeFile, err = utils.LockChangelogFile(utils.TranslatedFilePath(flags.ChangelogFile.Path, locale))
+				if err != nil {
+					return err
+				}
+				defer localeFile.Unlock()
+				localeEntries = localeFile.Entries
+			}
+
+			translatedCount := 0
+			for i, entry := range entries {
+				if len(entry.Changes) == 0 {
+					continue
+				}
+
+				var translated bool
+				if mode == utils.TranslationFiles {
+					var existing *models.ChangelogEntry
+					if index := utils.FindChangelogEntry(localeEntries, entry.Version); index != -1 {
+						existing = &localeEntries[index]
+					}
+					translated = utils.IsEntryTranslated(entry, existing)
+				} else {
+					translated = utils.IsEntryTranslatedField(entry, locale)
+				}
+				if translated {
+					if verbose {
+						utils.Eprintf("\u2192 Skipping version %s, it is already translated to %s\n", entry.Version, locale)
+					}
+					continue
+				}
+
+				translatedEntry, err := translate(entry, locale)
+				if err != nil {
+					return fmt.Errorf("Error translating changelog entry '%s' to '%s': %v", entry.Version, locale, err)
+				}
+				translatedCount++
+
+				if mode == utils.TranslationFiles {
+					localeEntries, _, err = utils.UpsertChangelogEntry(localeEntries, translatedEntry, utils.ConflictReplace)
+					if err != nil {
+						return err
+					}
+				} else {
+					entries[i].Translations = maps.Clone(entries[i].Translations)
+					if entries[i].Translations == nil {
+						entries[i].Translations = map[string][]models.ChangelogTranslation{}
+					}
+					entries[i].Translations[locale] = utils.ChangelogTranslations(entry, translatedEntry)
+					if entry.Summary != "" {
+						entries[i].SummaryTranslations = maps.Clone(entries[i].SummaryTranslations)
+						if entries[i].SummaryTranslations == nil {
+							entries[i].SummaryTranslations = map[string]models.SummaryTranslation{}
+						}
+						entries[i].SummaryTranslations[locale] = utils.SummaryTranslation(entry, translatedEntry)
+					}
+				}
+
+				if verbose {
+					utils.Eprintf("%s AI Translated changelog entry %s to %s\n", color.GreenString("\u2713"), color.CyanString(entry.Version), locale)
+				}
+			}
+
+			if mode == utils.TranslationFiles && translatedCount > 0 {
+				err = utils.WriteChangelogFile(localeFile, utils.SortChangelogEntries(localeEntries), utils.WriteChangelogOptions{Backup: flags.Backup})
+				if err != nil {
+					return fmt.Errorf("Error writing changelog file '%s': %v", localeFile.Path, err)
+				}
+				utils.Eprintf("%s Translated %d entries to %s in '%s'\n", color.GreenString("\u2713"), translatedCount, locale, localeFile.Path)
+			} else if translatedCount > 0 {
+				err = writeEditedChangelog(flags, entries)
+				if err != nil {
+					return err
+				}
+				utils.Eprintf("%s Translated %d entries to %s in the 'translations' field of '%s'\n", color.GreenString("\u2713"), translatedCount, locale, flags.ChangelogFile.Path)
+			} else {
+				utils.Eprintf("%s All entries are already translated to %s\n", color.GreenString("\u2713"), locale)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(translateCmd)
+
+	addEditFlags(translateCmd)
+	translateCmd.Flags().StringSlice("to", nil, "Comma separated locale codes to translate to (e.g. de,ja or pt-BR)")
+	translateCmd.Flags().String("mode", "", "Where to store the translations: files (a changelog file per locale, e.g. changelog.de.json) or field (the 'translations' field of each entry) (default \"files\")")
+	translateCmd.Flags().StringP("provider", "p", "openai", "LLM provider (see chlog models for available options)")
+	translateCmd.Flags().StringP("model", "m", "", "LLM model (see chlog models for available options and defaults)")
+	translateCmd.Flags().String("apiKey", "", "API key for the LLM provider (can also be set via environment variable, see chlog models for details)")
+	translateCmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+}
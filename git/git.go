@@ -3,6 +3,7 @@ package git
 import (
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 )
 
@@ -54,6 +55,11 @@ func CommitHistoryWithDiff(from, to string) (string, error) {
 		return "", fmt.Errorf("Error getting commits: %v", err)
 	}
 
+	return CommitsHistoryWithDiff(commits)
+}
+
+// CommitsHistoryWithDiff returns the details and diffs of the commits, separated by "--- COMMIT ---"
+func CommitsHistoryWithDiff(commits []string) (string, error) {
 	var builder strings.Builder
 	for _, commit := range commits {
 		details, err := CommitDetails(commit)
@@ -66,3 +72,121 @@ func CommitHistoryWithDiff(from, to string) (string, error) {
 
 	return builder.String(), nil
 }
+
+func IsRepository() error {
+	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("Not inside a Git repository: %v", err)
+	}
+	return nil
+}
+
+func CommitExists(commit string) bool {
+	cmd := exec.Command("git", "cat-file", "-e", commit+"^{commit}")
+	return cmd.Run() == nil
+}
+
+// ShortHash returns the abbreviated commit hash of the reference
+func ShortHash(ref string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--short", ref+"^{commit}")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Error resolving reference '%s': %v", ref, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SameCommit reports whether two (possibly abbreviated) commit hashes refer to the same commit
+func SameCommit(a, b string) bool {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if len(a) < 7 || len(b) < 7 {
+		return a == b
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// NewestCommit returns the full hash of the most recent of the commits: the commits are sorted by commit date, and a
+// commit that descends from the most recent one so far (e.g. with an older commit date after a rebase) replaces it
+func NewestCommit(commits []string) (string, error) {
+	if len(commits) == 0 {
+		return "", fmt.Errorf("No commits")
+	}
+	args := []string{"rev-list", "--no-walk=sorted"}
+	for _, commit := range commits {
+		args = append(args, commit+"^{commit}")
+	}
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("Error resolving commits: %v", err)
+	}
+	sorted := strings.Fields(string(out))
+	if len(sorted) == 0 {
+		return "", fmt.Errorf("No commits found")
+	}
+
+	newest := sorted[0]
+	for _, commit := range sorted[1:] {
+		if exec.Command("git", "merge-base", "--is-ancestor", newest, commit).Run() == nil {
+			newest = commit
+		}
+	}
+	return newest, nil
+}
+
+// CommitSubject returns the first line of the commit message
+func CommitSubject(commit string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%s", commit)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Error getting commit subject: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RemoteURL returns the URL of the origin remote
+func RemoteURL() (string, error) {
+	cmd := exec.Command("git", "remote", "get-url", "origin")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Error getting remote URL: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// MergeCommitRange returns the full hashes of the merge commits between the references
+func MergeCommitRange(from, to string) ([]string, error) {
+	cmd := exec.Command("git", "rev-list", "--merges", fmt.Sprintf("%s..%s", from, to))
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting merge commits: %v", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// Person is a commit author or co-author
+type Person struct {
+	Name  string
+	Email string
+}
+
+var coAuthoredByRegex = regexp.MustCompile(`(?im)^\s*co-authored-by:\s*(.+?)\s*<([^>]+)>\s*$`)
+
+// CommitAuthors returns the author of the commit followed by the co-authors from its Co-authored-by trailers
+func CommitAuthors(commit string) ([]Person, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%an%x00%ae%x00%B", commit)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting commit authors: %v", err)
+	}
+	parts := strings.SplitN(string(out), "\x00", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("Error getting commit authors: unexpected output of git log")
+	}
+
+	authors := []Person{{Name: strings.TrimSpace(parts[0]), Email: strings.TrimSpace(parts[1])}}
+	for _, match := range coAuthoredByRegex.FindAllStringSubmatch(parts[2], -1) {
+		authors = append(authors, Person{Name: match[1], Email: match[2]})
+	}
+	return authors, nil
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ReferencePattern matches references to a tracker (e.g. #123 or PROJ-456) in commit messages.
+// The first group of the regex (or the whole match if there is none) is the issue ID used in the URL template, and the
+// reference ends with it.
+type ReferencePattern struct {
+	Type  string
+	Regex *regexp.Regexp
+	// URL is the URL template of the issue, {id} is replaced with the issue ID
+	URL string
+}
+
+// Reference is a reference to an issue or ticket in a commit message
+type Reference struct {
+	Type string
+	// Text is the reference as written in the commit message (e.g. #123)
+	Text string
+	URL  string
+}
+
+// referenceTrailerRegex matches trailers that reference issues (e.g. "Fixes: #123" or "Refs: https://...")
+var referenceTrailerRegex = regexp.MustCompile(`(?im)^\s*(?:fixes|fixed|closes|closed|resolves|resolved|refs|references|related-to|see-also)\s*:\s*(.+?)\s*$`)
+
+var urlRegex = regexp.MustCompile(`https?://\S+`)
+
+// ParseReferences returns the unique references of the patterns in the message. A reference matched by several patterns
+// is returned with the type of the first one. URLs in reference trailers (e.g. "Fixes: https://tracker/issue/1") that
+// do not match a pattern are returned as "link" references.
+func ParseReferences(message string, patterns []ReferencePattern) []Reference {
+	references := []Reference{}
+	seen := map[string]bool{}
+	add := func(reference Reference) {
+		if !seen[reference.Text] {
+			seen[reference.Text] = true
+			references = append(references, reference)
+		}
+	}
+
+	for _, pattern := range patterns {
+		for _, match := range pattern.Regex.FindAllStringSubmatchIndex(message, -1) {
+			start, end := match[0], match[1]
+			id := message[start:end]
+			if len(match) > 3 && match[2] >= 0 && match[3] > match[2] {
+				id = message[match[2]:match[3]]
+				// Patterns can match the characters after the reference (e.g. the closing bracket of "[PROJ-1]")
+				end = match[3]
+			}
+			// Patterns can also match the characters before the reference to only match whole references (e.g. " #123")
+			text := strings.TrimLeftFunc(message[start:end], func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '#'
+			})
+			reference := Reference{Type: pattern.Type, Text: strings.TrimSpace(text)}
+			if pattern.URL != "" {
+				reference.URL = strings.ReplaceAll(pattern.URL, "{id}", id)
+			}
+			add(reference)
+		}
+	}
+
+	for _, trailer := range referenceTrailerRegex.FindAllStringSubmatch(message, -1) {
+		for _, url := range urlRegex.FindAllString(trailer[1], -1) {
+			matched := false
+			for _, pattern := range patterns {
+				if pattern.Regex.MatchString(url) {
+					matched = true
+				}
+			}
+			if !matched {
+				add(Reference{Type: "link", Text: url, URL: url})
+			}
+		}
+	}
+	return references
+}
+
+// CommitMessage returns the full message of the commit
+func CommitMessage(commit string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%B", commit)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Error getting commit message: %v", err)
+	}
+	return string(out), nil
+}
+
+// CommitReferences returns the references of the patterns in the message of the commit
+func CommitReferences(commit string, patterns []ReferencePattern) ([]Reference, error) {
+	message, err := CommitMessage(commit)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReferences(message, patterns), nil
+}
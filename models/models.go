@@ -1,24 +1,274 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
 	"github.com/invopop/jsonschema"
 )
 
 type ChangelogChange struct {
-	ID          string   `json:"id" jsonschema:"description=The unique identifier of the change. Leave as empty string."`
-	Title       string   `json:"title" jsonschema:"description=The title of the change. Should be succint."`
-	Description string   `json:"description" jsonschema:"description=End-user friendly description of the change. Should be more verbose."`
-	Impact      string   `json:"impact" jsonschema:"description=The impact of the change. Describe what and how the change affects the user or usage of the software."`
-	Commits     []string `json:"commits" jsonschema:"description=List of commit hashes associated with this change. Must have at least one value."`
-	Tags        []string `json:"tags" jsonschema:"description=Tags associated with this change"`
+	ID          string      `json:"id" toml:"id" jsonschema:"description=The unique identifier of the change. Leave as empty string."`
+	Title       string      `json:"title" toml:"title" jsonschema:"description=The title of the change. Should be succinct."`
+	Description string      `json:"description" toml:"description" jsonschema:"description=End-user friendly description of the change. Should be more verbose."`
+	Impact      string      `json:"impact" toml:"impact" jsonschema:"description=The impact of the change. Describe what and how the change affects the user or usage of the software."`
+	Commits     []string    `json:"commits" toml:"commits" jsonschema:"description=List of commit hashes associated with this change. Must have at least one value."`
+	Tags        []string    `json:"tags" toml:"tags" jsonschema:"description=Tags associated with this change"`
+	Importance  string      `json:"importance,omitempty" toml:"importance,omitempty" jsonschema:"enum=major,enum=minor,enum=trivial,description=How important the change is to the users of the software: major (changes most users notice)\\, minor (smaller improvements and fixes) or trivial (changes users barely notice such as refactors or typo fixes)."`
+	Internal    bool        `json:"internal,omitempty" toml:"internal,omitempty" jsonschema:"description=True if the change only matters to the team building the software (e.g. refactors\\, tests\\, CI or internal tooling) and should not be shown to end users."`
+	Authors     []Author    `json:"git_authors,omitempty" toml:"git_authors,omitempty" chlog:"stored" jsonschema:"description=The authors and co-authors of the commits of the change."`
+	References  []Reference `json:"references,omitempty" toml:"references,omitempty" chlog:"stored" jsonschema:"description=The issues and tickets referenced by the commits of the change."`
+	// Migration is the upgrade guide of breaking and deprecated changes, generated in a follow-up request
+	Migration *Migration `json:"migration,omitempty" toml:"migration,omitempty" chlog:"stored" jsonschema:"description=How to upgrade past a breaking or deprecated change."`
+	// Security is the advisory information of security changes, generated in a follow-up request
+	Security *SecurityAdvisory `json:"security,omitempty" toml:"security,omitempty" chlog:"stored" jsonschema:"description=The advisory information of a security change."`
+	// Extra holds any additional fields users added to the change (e.g. links or authors), so they are kept when the file is rewritten
+	Extra map[string]json.RawMessage `json:"-" toml:"-"`
+}
+
+// Importance levels of changes, ImportanceLevels is ordered from the least to the most important
+const (
+	ImportanceTrivial = "trivial"
+	ImportanceMinor   = "minor"
+	ImportanceMajor   = "major"
+)
+
+var ImportanceLevels = []string{ImportanceTrivial, ImportanceMinor, ImportanceMajor}
+
+// Author is an author or co-author of the commits of a change
+type Author struct {
+	Name  string `json:"name" toml:"name" jsonschema:"description=The name of the author."`
+	Email string `json:"email" toml:"email" jsonschema:"description=The email of the author."`
+	// Handle is the username of the author (e.g. on GitHub) from the author_handles of the config file
+	Handle string `json:"handle,omitempty" toml:"handle,omitempty" jsonschema:"description=The username of the author (e.g. on GitHub)."`
+}
+
+// Reference is an issue or ticket referenced by the commits of a change
+type Reference struct {
+	Type string `json:"type" toml:"type" jsonschema:"description=The type of tracker (e.g. github or jira)."`
+	ID   string `json:"id" toml:"id" jsonschema:"description=The reference as written in the commit message (e.g. #123 or PROJ-456)."`
+	URL  string `json:"url,omitempty" toml:"url,omitempty" jsonschema:"description=The URL of the issue or ticket."`
+}
+
+// Migration is the upgrade guide of a breaking or deprecated change
+type Migration struct {
+	Summary string   `json:"summary" toml:"summary" jsonschema:"description=What changed and who is affected by it."`
+	Before  string   `json:"before,omitempty" toml:"before,omitempty" jsonschema:"description=A snippet of the usage (code\\, configuration or command) before the change. Leave as empty string if there is no usage to show."`
+	After   string   `json:"after,omitempty" toml:"after,omitempty" jsonschema:"description=A snippet of the same usage after the change. Leave as empty string if there is no usage to show."`
+	Actions []string `json:"actions" toml:"actions" jsonschema:"description=The steps users must take to upgrade\\, in order. Must have at least one value."`
+}
+
+// Severity levels of security advisories, SeverityLevels is ordered from the least to the most severe
+const (
+	SeverityLow      = "low"
+	SeverityModerate = "moderate"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+var SeverityLevels = []string{SeverityLow, SeverityModerate, SeverityHigh, SeverityCritical}
+
+// SecurityAdvisory is the advisory information of a security change
+type SecurityAdvisory struct {
+	// IDs are read from the commit messages rather than generated
+	IDs       []string `json:"ids,omitempty" toml:"ids,omitempty" chlog:"stored" jsonschema:"description=The CVE and GHSA IDs referenced by the commits of the change."`
+	Summary   string   `json:"summary" toml:"summary" jsonschema:"description=A one line summary of the vulnerability that was fixed."`
+	Component string   `json:"component" toml:"component" jsonschema:"description=The affected component (e.g. a package\\, module\\, command or endpoint)."`
+	Severity  string   `json:"severity" toml:"severity" jsonschema:"enum=low,enum=moderate,enum=high,enum=critical,description=The estimated severity of the vulnerability."`
+}
+
+// ChangelogSummary is the overview of an entry
+type ChangelogSummary struct {
+	Summary    string   `json:"summary" toml:"summary" jsonschema:"description=A short human friendly paragraph giving an overview of the release."`
+	Highlights []string `json:"highlights" toml:"highlights" jsonschema:"description=The IDs of the most important changes\\, most important first."`
+}
+
+// ChangelogTranslation is the translation of the text of a change, the change is matched by its ID
+type ChangelogTranslation struct {
+	ID          string `json:"id" toml:"id" jsonschema:"description=The ID of the translated change."`
+	Title       string `json:"title" toml:"title" jsonschema:"description=The translated title of the change."`
+	Description string `json:"description" toml:"description" jsonschema:"description=The translated description of the change."`
+	Impact      string `json:"impact" toml:"impact" jsonschema:"description=The translated impact of the change."`
+	// Migration and SecuritySummary are only set for changes with an upgrade guide or advisory information
+	Migration       *MigrationTranslation `json:"migration,omitempty" toml:"migration,omitempty" jsonschema:"description=The translated upgrade guide of the change. Leave the summary as empty string and the actions empty if the change has no upgrade guide."`
+	SecuritySummary string                `json:"security_summary,omitempty" toml:"security_summary,omitempty" jsonschema:"description=The translated security summary of the change. Leave as empty string if the change has no security information."`
+	// SourceHash is the hash of the text the change was translated from, so translations of edited changes are redone
+	SourceHash string `json:"source_hash,omitempty" toml:"source_hash,omitempty" chlog:"stored" jsonschema:"description=The hash of the text of the change the translation was made from."`
+}
+
+// MigrationTranslation is the translation of the text of an upgrade guide, the before and after snippets are not translated
+type MigrationTranslation struct {
+	Summary string   `json:"summary" toml:"summary" jsonschema:"description=The translated summary of the upgrade guide."`
+	Actions []string `json:"actions" toml:"actions" jsonschema:"description=The translated actions of the upgrade guide\\, in the same order."`
+}
+
+// SummaryTranslation is the translation of the summary of an entry
+type SummaryTranslation struct {
+	Summary string `json:"summary" toml:"summary" jsonschema:"description=The translated summary of the entry."`
+	// SourceHash is the hash of the summary the translation was made from
+	SourceHash string `json:"source_hash,omitempty" toml:"source_hash,omitempty" jsonschema:"description=The hash of the summary the translation was made from."`
+}
+
+// ChangelogEntryTranslation is the translation of the text of an entry, as returned by the LLM
+type ChangelogEntryTranslation struct {
+	Summary string                 `json:"summary" toml:"summary" jsonschema:"description=The translated summary of the entry. Leave as empty string if the entry has no summary."`
+	Changes []ChangelogTranslation `json:"changes" toml:"changes" jsonschema:"description=The translated changes\\, one for each change of the entry in the same order."`
 }
 
 type ChangelogEntry struct {
-	Version string            `json:"version" jsonschema:"description=The version number of the release. Leave as empty string."`
-	Date    string            `json:"date" jsonschema:"description=The date of the release. Leave as empty string."`
-	FromRef string            `json:"from_ref" jsonschema:"description=The starting commit reference for the changelog entry. Leave as empty string."`
-	ToRef   string            `json:"to_ref" jsonschema:"description=The ending commit reference for the changelog entry. Leave as empty string."`
-	Changes []ChangelogChange `json:"changes" jsonschema:"description=Generate a list of changes following the schema using the provided git commits and diffs."`
+	Version string            `json:"version" toml:"version" jsonschema:"description=The version number of the release. Leave as empty string."`
+	Date    string            `json:"date" toml:"date" jsonschema:"description=The date of the release. Leave as empty string."`
+	FromRef string            `json:"from_ref" toml:"from_ref" jsonschema:"description=The starting commit reference for the changelog entry. Leave as empty string."`
+	ToRef   string            `json:"to_ref" toml:"to_ref" jsonschema:"description=The ending commit reference for the changelog entry. Leave as empty string."`
+	Changes []ChangelogChange `json:"changes" toml:"changes" jsonschema:"description=Generate a list of changes following the schema using the provided git commits and diffs."`
+	// Audience is the audience the changes were written for, and Variants holds the changes written for other audiences
+	Audience string                       `json:"audience,omitempty" toml:"audience,omitempty" chlog:"stored" jsonschema:"description=The audience the changes were written for (e.g. end-user\\, developer or internal)."`
+	Variants map[string][]ChangelogChange `json:"variants,omitempty" toml:"variants,omitempty" chlog:"stored" jsonschema:"description=The changes written for other audiences\\, by audience."`
+	// Language is the locale code of the language the changes were written in, and Translations holds translations of the changes
+	Language     string                            `json:"language,omitempty" toml:"language,omitempty" chlog:"stored" jsonschema:"description=The locale code of the language the changes were written in (e.g. en or de)."`
+	Translations map[string][]ChangelogTranslation `json:"translations,omitempty" toml:"translations,omitempty" chlog:"stored" jsonschema:"description=Translations of the changes\\, by locale code."`
+	// SummaryTranslations holds translations of the summary, and SourceHash is set on the entries of translated changelog
+	// files (see chlog translate) to the hash of the text of the entry they were translated from
+	SummaryTranslations map[string]SummaryTranslation `json:"summary_translations,omitempty" toml:"summary_translations,omitempty" chlog:"stored" jsonschema:"description=Translations of the summary\\, by locale code."`
+	SourceHash          string                        `json:"source_hash,omitempty" toml:"source_hash,omitempty" chlog:"stored" jsonschema:"description=The hash of the text of the entry this translated entry was made from."`
+	// Contributors are the authors of all the changes of the entry
+	Contributors []Author `json:"contributors,omitempty" toml:"contributors,omitempty" chlog:"stored" jsonschema:"description=The authors of all the changes of the entry."`
+	// Summary and Highlights give an overview of the entry, they are generated in a follow-up request
+	Summary    string   `json:"summary,omitempty" toml:"summary,omitempty" chlog:"stored" jsonschema:"description=A short human friendly overview of the release."`
+	Highlights []string `json:"highlights,omitempty" toml:"highlights,omitempty" chlog:"stored" jsonschema:"description=The IDs of the most important changes of the release\\, most important first."`
+	// Extra holds any additional fields users added to the entry (e.g. links), so they are kept when the file is rewritten
+	Extra map[string]json.RawMessage `json:"-" toml:"-"`
+}
+
+// Aliases without the JSON methods, used to (un)marshal the known fields
+type changelogChangeFields ChangelogChange
+type changelogEntryFields ChangelogEntry
+
+func (c *ChangelogChange) UnmarshalJSON(data []byte) error {
+	var fields changelogChangeFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	extra, err := unknownFields[changelogChangeFields](data)
+	if err != nil {
+		return err
+	}
+	*c = ChangelogChange(fields)
+	c.Extra = extra
+	return nil
+}
+
+func (c ChangelogChange) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(changelogChangeFields(c))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtraFields(data, c.Extra)
+}
+
+func (e *ChangelogEntry) UnmarshalJSON(data []byte) error {
+	var fields changelogEntryFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	extra, err := unknownFields[changelogEntryFields](data)
+	if err != nil {
+		return err
+	}
+	*e = ChangelogEntry(fields)
+	e.Extra = extra
+	return nil
+}
+
+func (e ChangelogEntry) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(changelogEntryFields(e))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtraFields(data, e.Extra)
+}
+
+// HasExtraFields reports whether the entry or any of its changes have additional fields
+func (e ChangelogEntry) HasExtraFields() bool {
+	if len(e.Extra) > 0 {
+		return true
+	}
+	for _, change := range e.Changes {
+		if len(change.Extra) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFieldNames returns the JSON names of the fields of struct type T
+func jsonFieldNames[T any]() map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeFor[T]()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// unknownFields returns the fields of the JSON object that are not fields of struct type T
+func unknownFields[T any](data []byte) (map[string]json.RawMessage, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	known := jsonFieldNames[T]()
+	var extra map[string]json.RawMessage
+	for key, value := range raw {
+		if known[key] {
+			continue
+		}
+		if extra == nil {
+			extra = map[string]json.RawMessage{}
+		}
+		extra[key] = value
+	}
+	return extra, nil
+}
+
+// appendExtraFields appends the extra fields (sorted by key) to the end of the JSON object
+func appendExtraFields(data []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return data, nil
+	}
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, key := range keys {
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(",")
+		buf.Write(keyJSON)
+		buf.WriteString(":")
+		buf.Write(extra[key])
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+type ChangelogFile struct {
+	Title       string           `json:"title" toml:"title" jsonschema:"description=The title of the changelog."`
+	Description string           `json:"description" toml:"description" jsonschema:"description=The description of the changelog."`
+	Repository  string           `json:"repository" toml:"repository" jsonschema:"description=The URL of the repository."`
+	Entries     []ChangelogEntry `json:"entries" toml:"entries" jsonschema:"description=The changelog entries\\, most recent first."`
 }
 
 func GenerateSchema[T any]() *jsonschema.Schema {
@@ -31,4 +281,75 @@ func GenerateSchema[T any]() *jsonschema.Schema {
 	return schema
 }
 
-var ChangelogEntrySchema = GenerateSchema[ChangelogEntry]()
+// GenerateResponseSchema generates the schema of T for LLM responses. Fields tagged `chlog:"stored"` are set by chlog
+// rather than the LLM, so they are removed, and all other properties are required as structured outputs expect.
+func GenerateResponseSchema[T any]() *jsonschema.Schema {
+	schema := GenerateSchema[T]()
+	omitStoredFields(reflect.TypeFor[T](), schema)
+	return schema
+}
+
+func omitStoredFields(t reflect.Type, schema *jsonschema.Schema) {
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		if schema.Items != nil {
+			schema = schema.Items
+		}
+	}
+	if t.Kind() != reflect.Struct || schema.Properties == nil {
+		return
+	}
+
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		property, ok := schema.Properties.Get(name)
+		if name == "" || name == "-" || !ok {
+			continue
+		}
+		if field.Tag.Get("chlog") == "stored" {
+			schema.Properties.Delete(name)
+			continue
+		}
+		required = append(required, name)
+		omitStoredFields(field.Type, property)
+	}
+	schema.Required = required
+}
+
+var ChangelogEntrySchema = GenerateResponseSchema[ChangelogEntry]()
+
+var MigrationSchema = GenerateResponseSchema[Migration]()
+
+var SummarySchema = GenerateResponseSchema[ChangelogSummary]()
+
+var SecurityAdvisorySchema = GenerateResponseSchema[SecurityAdvisory]()
+
+var TranslationSchema = GenerateResponseSchema[ChangelogEntryTranslation]()
+
+// ChangelogFileSchemaID is the published location of the changelog file schema
+const ChangelogFileSchemaID = "https://raw.githubusercontent.com/ammar-ahmed22/chlog/main/changelog.schema.json"
+
+// generateChangelogFileSchema generates the schema for changelog files, which can either be an array of entries
+// or an object with an "entries" key. Additional fields are allowed anywhere so users can store extra metadata.
+func generateChangelogFileSchema() *jsonschema.Schema {
+	reflector := jsonschema.Reflector{
+		AllowAdditionalProperties: true,
+		DoNotReference:            true,
+	}
+	fileSchema := reflector.Reflect(&ChangelogFile{})
+	fileSchema.Version = ""
+	fileSchema.Required = []string{"entries"}
+	entriesSchema, _ := fileSchema.Properties.Get("entries")
+
+	return &jsonschema.Schema{
+		Version:     jsonschema.Version,
+		ID:          ChangelogFileSchemaID,
+		Title:       "chlog changelog file",
+		Description: "A changelog file managed by chlog. Either an array of changelog entries or an object with an 'entries' key.",
+		OneOf:       []*jsonschema.Schema{entriesSchema, fileSchema},
+	}
+}
+
+var ChangelogFileSchema = generateChangelogFileSchema()
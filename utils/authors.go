@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+)
+
+// GetConfigAuthorHandles returns the handles (e.g. GitHub usernames) by lowercased email from the 'author_handles' key of
+// the config file, a list of mappings with an email and a handle. The config file must already be loaded.
+func GetConfigAuthorHandles() (map[string]string, error) {
+	handles := map[string]string{}
+	if !viper.IsSet("author_handles") {
+		return handles, nil
+	}
+
+	items, ok := viper.Get("author_handles").([]any)
+	if !ok {
+		return nil, fmt.Errorf("Invalid 'author_handles' in config file. Expected a list of emails and handles")
+	}
+	for i, item := range items {
+		value, _ := item.(map[string]any)
+		email, _ := value["email"].(string)
+		handle, _ := value["handle"].(string)
+		email, handle = strings.TrimSpace(email), strings.TrimPrefix(strings.TrimSpace(handle), "@")
+		if email == "" || handle == "" {
+			return nil, fmt.Errorf("Invalid author handle %d in config file. Each author handle must have an 'email' and 'handle' key", i+1)
+		}
+		handles[strings.ToLower(email)] = handle
+	}
+	return handles, nil
+}
+
+// AuthorResolver returns the authors of commits from git, caching them by commit
+type AuthorResolver struct {
+	// Handles maps lowercased emails to handles
+	Handles map[string]string
+	commits map[string][]models.Author
+}
+
+func NewAuthorResolver(handles map[string]string) *AuthorResolver {
+	return &AuthorResolver{Handles: handles, commits: map[string][]models.Author{}}
+}
+
+// CommitAuthors returns the author and co-authors of the commit
+func (r *AuthorResolver) CommitAuthors(commit string) ([]models.Author, error) {
+	if authors, ok := r.commits[commit]; ok {
+		return authors, nil
+	}
+	people, err := git.CommitAuthors(commit)
+	if err != nil {
+		return nil, err
+	}
+	authors := lo.Map(people, func(person git.Person, _ int) models.Author {
+		return models.Author{Name: person.Name, Email: person.Email, Handle: r.Handles[strings.ToLower(person.Email)]}
+	})
+	r.commits[commit] = authors
+	return authors, nil
+}
+
+// AttachChangeAuthors sets the authors of each change (and variant) of the entry from its commits, and the contributors
+// of the entry. Commits that cannot be found in the repository are skipped.
+func (r *AuthorResolver) AttachChangeAuthors(entry *models.ChangelogEntry) {
+	attach := func(changes []models.ChangelogChange) {
+		for i, change := range changes {
+			authors := []models.Author{}
+			for _, commit := range change.Commits {
+				commitAuthors, err := r.CommitAuthors(commit)
+				if err != nil {
+					continue
+				}
+				authors = append(authors, commitAuthors...)
+			}
+			changes[i].Authors = uniqueAuthors(authors)
+		}
+	}
+
+	attach(entry.Changes)
+	for _, changes := range entry.Variants {
+		attach(changes)
+	}
+	entry.Contributors = ChangelogContributors(entry.Changes)
+}
+
+// ChangelogContributors returns the unique authors of the changes, in order of appearance, or nil if there are none
+func ChangelogContributors(changes []models.ChangelogChange) []models.Author {
+	authors := []models.Author{}
+	for _, change := range changes {
+		authors = append(authors, change.Authors...)
+	}
+	if len(authors) == 0 {
+		return nil
+	}
+	return uniqueAuthors(authors)
+}
+
+// RefreshContributors updates the contributors of the entries that have them from the authors of their changes
+// (e.g. after changes were removed or moved)
+func RefreshContributors(entries []models.ChangelogEntry) {
+	for i, entry := range entries {
+		if entry.Contributors != nil || lo.SomeBy(entry.Changes, func(change models.ChangelogChange) bool { return len(change.Authors) > 0 }) {
+			entries[i].Contributors = ChangelogContributors(entry.Changes)
+		}
+	}
+}
+
+// uniqueAuthors removes the authors with the same email (case-insensitive), keeping the first one
+func uniqueAuthors(authors []models.Author) []models.Author {
+	return lo.UniqBy(authors, func(author models.Author) string {
+		return strings.ToLower(author.Email)
+	})
+}
+
+// AuthorDisplayName returns the handle of the author with an @ prefix, or the name if there is no handle
+func AuthorDisplayName(author models.Author) string {
+	if author.Handle != "" {
+		return "@" + author.Handle
+	}
+	return author.Name
+}
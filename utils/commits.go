@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// MissingCommitsStrategy is what chlog generate does when commits of the range are not referenced by any generated change
+type MissingCommitsStrategy string
+
+const (
+	MissingCommitsWarn  MissingCommitsStrategy = "warn"
+	MissingCommitsError MissingCommitsStrategy = "error"
+	// MissingCommitsRetry asks the model again for the changes of the missing commits
+	MissingCommitsRetry MissingCommitsStrategy = "retry"
+)
+
+var MissingCommitsStrategies = []MissingCommitsStrategy{MissingCommitsWarn, MissingCommitsError, MissingCommitsRetry}
+
+// ParseMissingCommitsStrategy parses the strategy, an empty value defaults to MissingCommitsWarn
+func ParseMissingCommitsStrategy(value string) (MissingCommitsStrategy, error) {
+	if value == "" {
+		return MissingCommitsWarn, nil
+	}
+	for _, strategy := range MissingCommitsStrategies {
+		if string(strategy) == value {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid value '%s' for '--on-missing-commits'. Valid values are: %s", value, strings.Join(lo.Map(MissingCommitsStrategies, func(strategy MissingCommitsStrategy, _ int) string {
+		return string(strategy)
+	}), ", "))
+}
+
+// InvalidCommit is a commit reference of a change that is not one of the commits of the range
+type InvalidCommit struct {
+	Change string
+	Commit string
+}
+
+// CommitValidation is the result of ValidateChangelogCommits
+type CommitValidation struct {
+	// Expanded is the number of abbreviated or malformed commit references that were replaced with the full hash
+	Expanded int
+	// Invalid are the commit references that were removed
+	Invalid []InvalidCommit
+	// NoCommits are the titles of the changes that are left without commits
+	NoCommits []string
+	// Missing are the commits of the range that no change references
+	Missing []string
+}
+
+// ResolveCommit returns the full hash of the commit (one of commits) that the reference is an abbreviation of.
+// Surrounding text is ignored (e.g. "abc1234: fix typo"), and the reference must match exactly one commit.
+func ResolveCommit(reference string, commits []string) (string, bool) {
+	fields := strings.FieldsFunc(strings.ToLower(reference), func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f')
+	})
+	for _, field := range fields {
+		if len(field) < 4 {
+			continue
+		}
+		matches := lo.Filter(commits, func(commit string, _ int) bool {
+			return strings.HasPrefix(strings.ToLower(commit), field)
+		})
+		if len(matches) == 1 {
+			return matches[0], true
+		}
+	}
+	return "", false
+}
+
+// ValidateChangelogCommits replaces the commit references of the changes (and variants) of the entry with the full
+// hashes of the commits of the range, removes the references that are not in the range and reports the commits of
+// the range that no change references. The ignored commits (e.g. merge commits) are not reported as missing.
+func ValidateChangelogCommits(entry *models.ChangelogEntry, commits []string, ignored []string) CommitValidation {
+	validation := CommitValidation{}
+	validateChanges := func(changes []models.ChangelogChange) {
+		for i, change := range changes {
+			resolved := []string{}
+			for _, commit := range change.Commits {
+				full, ok := ResolveCommit(commit, commits)
+				if !ok {
+					validation.Invalid = append(validation.Invalid, InvalidCommit{Change: change.Title, Commit: commit})
+					continue
+				}
+				if full != commit {
+					validation.Expanded++
+				}
+				resolved = append(resolved, full)
+			}
+			changes[i].Commits = lo.Uniq(resolved)
+			if len(resolved) == 0 {
+				validation.NoCommits = append(validation.NoCommits, change.Title)
+			}
+		}
+	}
+
+	validateChanges(entry.Changes)
+	for _, changes := range entry.Variants {
+		validateChanges(changes)
+	}
+
+	validation.Missing = MissingCommits(entry.Changes, commits, ignored)
+	return validation
+}
+
+// ChangesCommits returns the commits referenced by the changes
+func ChangesCommits(changes []models.ChangelogChange) []string {
+	commits := []string{}
+	for _, change := range changes {
+		commits = append(commits, change.Commits...)
+	}
+	return commits
+}
+
+// MissingCommits returns the commits that are not referenced by any of the changes and are not ignored
+func MissingCommits(changes []models.ChangelogChange, commits []string, ignored []string) []string {
+	return lo.Filter(commits, func(commit string, _ int) bool {
+		referenced := lo.ContainsBy(changes, func(change models.ChangelogChange) bool {
+			return lo.ContainsBy(change.Commits, func(reference string) bool {
+				return git.SameCommit(reference, commit)
+			})
+		})
+		return !referenced && !lo.ContainsBy(ignored, func(ignoredCommit string) bool {
+			return git.SameCommit(ignoredCommit, commit)
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+)
+
+var testCommits = []string{
+	"abc1234def5678abc1234def5678abc1234def56",
+	"abc1999000011112222333344445555666677778",
+	"0123456789abcdef0123456789abcdef01234567",
+}
+
+func TestResolveCommit(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		want      string
+		wantOK    bool
+	}{
+		{"full hash", testCommits[0], testCommits[0], true},
+		{"abbreviated", "abc1234", testCommits[0], true},
+		{"uppercase", "ABC1234", testCommits[0], true},
+		{"surrounding text", "abc1234: fix typo", testCommits[0], true},
+		{"longer abbreviation", "abc19", testCommits[1], true},
+		{"prefix of several commits", "abc1", "", false},
+		{"too short", "abc", "", false},
+		{"unknown commit", "fedcba9", "", false},
+		{"no hash", "fix typo", "", false},
+		{"second field", "see 0123456", testCommits[2], true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := ResolveCommit(test.reference, testCommits)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("ResolveCommit(%q) = %q, %v, want %q, %v", test.reference, got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateChangelogCommits(t *testing.T) {
+	entry := models.ChangelogEntry{
+		Changes: []models.ChangelogChange{
+			{Title: "A", Commits: []string{"abc1234", testCommits[0]}},
+			{Title: "B", Commits: []string{"fedcba9"}},
+		},
+		Variants: map[string][]models.ChangelogChange{
+			"developer": {{Title: "A", Commits: []string{"(abc1999)"}}},
+		},
+	}
+
+	validation := ValidateChangelogCommits(&entry, testCommits, []string{testCommits[2]})
+	want := CommitValidation{
+		Expanded:  2,
+		Invalid:   []InvalidCommit{{Change: "B", Commit: "fedcba9"}},
+		NoCommits: []string{"B"},
+		Missing:   []string{testCommits[1]},
+	}
+	if !reflect.DeepEqual(validation, want) {
+		t.Errorf("ValidateChangelogCommits() = %+v, want %+v", validation, want)
+	}
+	if !reflect.DeepEqual(entry.Changes[0].Commits, testCommits[:1]) {
+		t.Errorf("commits = %v, want the full hash once", entry.Changes[0].Commits)
+	}
+	if !reflect.DeepEqual(entry.Variants["developer"][0].Commits, testCommits[1:2]) {
+		t.Errorf("variant commits = %v, want the full hash", entry.Variants["developer"][0].Commits)
+	}
+}
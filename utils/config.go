@@ -2,7 +2,10 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -63,3 +66,76 @@ func GetConfigFlagBool(cmd *cobra.Command, name string) (bool, error) {
 
 	return false, nil
 }
+
+// GetConfigTags returns the tags from the 'tags' key of the config file, or the default tags if it is not set.
+// Each tag is either a name or a mapping with a name and a description. The config file must already be loaded.
+func GetConfigTags() ([]ai.Tag, error) {
+	if !viper.IsSet("tags") {
+		return ai.DefaultTagDefinitions, nil
+	}
+
+	items, ok := viper.Get("tags").([]any)
+	if !ok {
+		return nil, fmt.Errorf("Invalid 'tags' in config file. Expected a list of tags")
+	}
+
+	tags := []ai.Tag{}
+	for i, item := range items {
+		var tag ai.Tag
+		switch value := item.(type) {
+		case string:
+			tag.Name = value
+		case map[string]any:
+			tag.Name, _ = value["name"].(string)
+			tag.Description, _ = value["description"].(string)
+		}
+		tag.Name = strings.TrimSpace(tag.Name)
+		if tag.Name == "" {
+			return nil, fmt.Errorf("Invalid tag %d in config file. Each tag must be a name or have a 'name' key", i+1)
+		}
+		for _, existing := range tags {
+			if existing.Name == tag.Name {
+				return nil, fmt.Errorf("Duplicate tag '%s' in config file", tag.Name)
+			}
+		}
+		tags = append(tags, tag)
+	}
+
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("Invalid 'tags' in config file. At least one tag is required")
+	}
+	return tags, nil
+}
+
+// GetConfigAudiences returns the built-in audiences and the audiences from the 'audiences' key of the config file.
+// Each audience is a mapping with a name and instructions, and replaces the built-in audience with the same name.
+// The config file must already be loaded.
+func GetConfigAudiences() ([]ai.Audience, error) {
+	audiences := append([]ai.Audience{}, ai.DefaultAudiences...)
+	if !viper.IsSet("audiences") {
+		return audiences, nil
+	}
+
+	items, ok := viper.Get("audiences").([]any)
+	if !ok {
+		return nil, fmt.Errorf("Invalid 'audiences' in config file. Expected a list of audiences")
+	}
+
+	for i, item := range items {
+		value, _ := item.(map[string]any)
+		name, _ := value["name"].(string)
+		instructions, _ := value["instructions"].(string)
+		audience := ai.Audience{Name: strings.TrimSpace(name), Instructions: strings.TrimSpace(instructions)}
+		if audience.Name == "" || audience.Instructions == "" {
+			return nil, fmt.Errorf("Invalid audience %d in config file. Each audience must have a 'name' and 'instructions' key", i+1)
+		}
+
+		index := lo.IndexOf(ai.AudienceNames(audiences), audience.Name)
+		if index == -1 {
+			audiences = append(audiences, audience)
+		} else {
+			audiences[index] = audience
+		}
+	}
+	return audiences, nil
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// ConflictStrategy is how a new entry is added to a changelog file that already has an entry with the same version
+type ConflictStrategy string
+
+const (
+	// ConflictError fails without changing the changelog file
+	ConflictError ConflictStrategy = "error"
+	// ConflictReplace replaces the existing entry with the new entry
+	ConflictReplace ConflictStrategy = "replace"
+	// ConflictMerge keeps the existing entry and its changes, adding only the changes of the new entry that are not in it yet
+	ConflictMerge ConflictStrategy = "merge"
+	// ConflictSkip keeps the existing entry and does not add the new entry
+	ConflictSkip ConflictStrategy = "skip"
+)
+
+var ConflictStrategies = []ConflictStrategy{ConflictError, ConflictReplace, ConflictMerge, ConflictSkip}
+
+func ParseConflictStrategy(value string) (ConflictStrategy, error) {
+	if value == "" {
+		return ConflictError, nil
+	}
+	strategy := ConflictStrategy(strings.ToLower(value))
+	if !lo.Contains(ConflictStrategies, strategy) {
+		return "", fmt.Errorf("Invalid conflict strategy '%s'. Supported strategies are: %v", value, ConflictStrategies)
+	}
+	return strategy, nil
+}
+
+// FindChangelogEntry returns the index of the entry with the given version, or -1 if there is none
+func FindChangelogEntry(entries []models.ChangelogEntry, version string) int {
+	_, index, found := lo.FindIndexOf(entries, func(entry models.ChangelogEntry) bool {
+		return entry.Version == version
+	})
+	if !found {
+		return -1
+	}
+	return index
+}
+
+// UpsertChangelogEntry adds the entry to the beginning of the entries, or resolves the conflict with an existing entry
+// of the same version using the strategy. It returns the updated entries and whether they changed.
+func UpsertChangelogEntry(entries []models.ChangelogEntry, entry models.ChangelogEntry, strategy ConflictStrategy) ([]models.ChangelogEntry, bool, error) {
+	index := FindChangelogEntry(entries, entry.Version)
+	if index == -1 {
+		// NOTE: Adding the new entry to the beginning. This is not good for performance but OK for POC.
+		return append([]models.ChangelogEntry{entry}, entries...), true, nil
+	}
+
+	switch strategy {
+	case ConflictReplace:
+		updated := append([]models.ChangelogEntry{}, entries...)
+		updated[index] = entry
+		return updated, true, nil
+	case ConflictMerge:
+		merged, added := MergeChangelogEntries(entries[index], entry)
+		if added == 0 {
+			return entries, false, nil
+		}
+		updated := append([]models.ChangelogEntry{}, entries...)
+		updated[index] = merged
+		return updated, true, nil
+	case ConflictSkip:
+		return entries, false, nil
+	default:
+		return nil, false, fmt.Errorf("Version '%s' already exists in the changelog file. Use '--on-conflict' to replace, merge or skip it", entry.Version)
+	}
+}
+
+// MergeChangelogEntries adds the changes of the generated entry that are not in the existing entry yet to the existing entry.
+// Changes are matched by ID or by a shared commit, so hand-edited changes of the existing entry are kept as is.
+// It returns the merged entry and the number of added changes.
+func MergeChangelogEntries(existing models.ChangelogEntry, generated models.ChangelogEntry) (models.ChangelogEntry, int) {
+	merged := existing
+	var added int
+	merged.Changes, added = mergeChangelogChanges(existing.Changes, generated.Changes)
+
+	// The changes written for other audiences are merged the same way
+	if merged.Audience == "" {
+		merged.Audience = generated.Audience
+	}
+	if len(generated.Variants) > 0 {
+		merged.Variants = maps.Clone(merged.Variants)
+		if merged.Variants == nil {
+			merged.Variants = map[string][]models.ChangelogChange{}
+		}
+		for audience, changes := range generated.Variants {
+			if audience != merged.Audience {
+				merged.Variants[audience], _ = mergeChangelogChanges(merged.Variants[audience], changes)
+			}
+		}
+	}
+
+	if merged.Date == "" {
+		merged.Date = generated.Date
+	}
+	if merged.FromRef == "" {
+		merged.FromRef = generated.FromRef
+	}
+	if merged.ToRef == "" {
+		merged.ToRef = generated.ToRef
+	}
+	return merged, added
+}
+
+func mergeChangelogChanges(existing []models.ChangelogChange, generated []models.ChangelogChange) ([]models.ChangelogChange, int) {
+	merged := append([]models.ChangelogChange{}, existing...)
+	added := 0
+	for _, change := range generated {
+		exists := lo.ContainsBy(merged, func(existingChange models.ChangelogChange) bool {
+			if change.ID != "" && existingChange.ID == change.ID {
+				return true
+			}
+			return len(lo.Intersect(existingChange.Commits, change.Commits)) > 0
+		})
+		if !exists {
+			merged = append(merged, change)
+			added++
+		}
+	}
+	return merged, added
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+func testConflictChange(id string, commits ...string) models.ChangelogChange {
+	return models.ChangelogChange{ID: id, Title: id, Commits: commits}
+}
+
+func changeIDs(changes []models.ChangelogChange) []string {
+	return lo.Map(changes, func(change models.ChangelogChange, _ int) string { return change.ID })
+}
+
+func TestParseConflictStrategy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ConflictStrategy
+		wantErr bool
+	}{
+		{"", ConflictError, false},
+		{"merge", ConflictMerge, false},
+		{"Replace", ConflictReplace, false},
+		{"overwrite", "", true},
+	}
+	for _, test := range tests {
+		got, err := ParseConflictStrategy(test.value)
+		if got != test.want || (err != nil) != test.wantErr {
+			t.Errorf("ParseConflictStrategy(%q) = %q, %v, want %q (error: %v)", test.value, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+func TestUpsertChangelogEntry(t *testing.T) {
+	entries := []models.ChangelogEntry{
+		{Version: "1.1.0", Changes: []models.ChangelogChange{testConflictChange("edited-a", "a"), testConflictChange("b", "b")}},
+		{Version: "1.0.0", Changes: []models.ChangelogChange{testConflictChange("c", "c")}},
+	}
+
+	tests := []struct {
+		name        string
+		entry       models.ChangelogEntry
+		strategy    ConflictStrategy
+		wantChanged bool
+		wantErr     bool
+		// wantVersions are the versions of the updated entries, and wantChanges the change IDs of the first one
+		wantVersions []string
+		wantChanges  []string
+	}{
+		{
+			name:         "new version",
+			entry:        models.ChangelogEntry{Version: "1.2.0", Changes: []models.ChangelogChange{testConflictChange("d", "d")}},
+			strategy:     ConflictError,
+			wantChanged:  true,
+			wantVersions: []string{"1.2.0", "1.1.0", "1.0.0"},
+			wantChanges:  []string{"d"},
+		},
+		{
+			name:     "error",
+			entry:    models.ChangelogEntry{Version: "1.1.0"},
+			strategy: ConflictError,
+			wantErr:  true,
+		},
+		{
+			name:         "replace",
+			entry:        models.ChangelogEntry{Version: "1.1.0", Changes: []models.ChangelogChange{testConflictChange("d", "d")}},
+			strategy:     ConflictReplace,
+			wantChanged:  true,
+			wantVersions: []string{"1.1.0", "1.0.0"},
+			wantChanges:  []string{"d"},
+		},
+		{
+			name: "merge",
+			entry: models.ChangelogEntry{Version: "1.1.0", Changes: []models.ChangelogChange{
+				testConflictChange("a", "a"), testConflictChange("b", "other"), testConflictChange("d", "d"),
+			}},
+			strategy:     ConflictMerge,
+			wantChanged:  true,
+			wantVersions: []string{"1.1.0", "1.0.0"},
+			wantChanges:  []string{"edited-a", "b", "d"},
+		},
+		{
+			name:         "merge without new changes",
+			entry:        models.ChangelogEntry{Version: "1.1.0", Changes: []models.ChangelogChange{testConflictChange("a", "a")}},
+			strategy:     ConflictMerge,
+			wantVersions: []string{"1.1.0", "1.0.0"},
+			wantChanges:  []string{"edited-a", "b"},
+		},
+		{
+			name:         "skip",
+			entry:        models.ChangelogEntry{Version: "1.1.0", Changes: []models.ChangelogChange{testConflictChange("d", "d")}},
+			strategy:     ConflictSkip,
+			wantVersions: []string{"1.1.0", "1.0.0"},
+			wantChanges:  []string{"edited-a", "b"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated, changed, err := UpsertChangelogEntry(entries, test.entry, test.strategy)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("UpsertChangelogEntry() error = %v, want error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if changed != test.wantChanged {
+				t.Errorf("UpsertChangelogEntry() changed = %v, want %v", changed, test.wantChanged)
+			}
+			versions := lo.Map(updated, func(entry models.ChangelogEntry, _ int) string { return entry.Version })
+			if !reflect.DeepEqual(versions, test.wantVersions) {
+				t.Errorf("versions = %v, want %v", versions, test.wantVersions)
+			}
+			if got := changeIDs(updated[0].Changes); !reflect.DeepEqual(got, test.wantChanges) {
+				t.Errorf("changes = %v, want %v", got, test.wantChanges)
+			}
+			if got := changeIDs(entries[0].Changes); !reflect.DeepEqual(got, []string{"edited-a", "b"}) {
+				t.Errorf("UpsertChangelogEntry() changed the original entries to %v", got)
+			}
+		})
+	}
+}
+
+func TestMergeChangelogEntries(t *testing.T) {
+	existing := models.ChangelogEntry{
+		Version:  "1.1.0",
+		ToRef:    "v1.1.0",
+		Audience: "end-user",
+		Changes:  []models.ChangelogChange{testConflictChange("a", "a")},
+		Variants: map[string][]models.ChangelogChange{"developer": {testConflictChange("a", "a")}},
+	}
+	generated := models.ChangelogEntry{
+		Version:  "1.1.0",
+		Date:     "2024-02-01",
+		FromRef:  "v1.0.0",
+		ToRef:    "HEAD",
+		Audience: "developer",
+		Changes:  []models.ChangelogChange{testConflictChange("b", "b")},
+		Variants: map[string][]models.ChangelogChange{
+			"end-user":  {testConflictChange("c", "c")},
+			"developer": {testConflictChange("a", "a"), testConflictChange("d", "d")},
+		},
+	}
+
+	merged, added := MergeChangelogEntries(existing, generated)
+	if added != 1 || !reflect.DeepEqual(changeIDs(merged.Changes), []string{"a", "b"}) {
+		t.Errorf("MergeChangelogEntries() changes = %v (%d added), want [a b] (1 added)", changeIDs(merged.Changes), added)
+	}
+	if merged.Audience != "end-user" || merged.Date != "2024-02-01" || merged.FromRef != "v1.0.0" || merged.ToRef != "v1.1.0" {
+		t.Errorf("MergeChangelogEntries() = audience %q, date %q, refs %s..%s, want the existing values with the missing ones filled in", merged.Audience, merged.Date, merged.FromRef, merged.ToRef)
+	}
+	if _, exists := merged.Variants["end-user"]; exists {
+		t.Error("MergeChangelogEntries() added a variant for the audience of the entry")
+	}
+	if got := changeIDs(merged.Variants["developer"]); !reflect.DeepEqual(got, []string{"a", "d"}) {
+		t.Errorf("developer variant = %v, want [a d]", got)
+	}
+	if got := changeIDs(existing.Variants["developer"]); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("MergeChangelogEntries() changed the existing variants to %v", got)
+	}
+}
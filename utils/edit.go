@@ -0,0 +1,398 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type EditFlags struct {
+	ChangelogFile *ParsedChangelogFile
+	Backup        bool
+	Tags          []ai.Tag
+	// IDStrategy is the 'id-strategy' of the config file, used for the IDs of new changes
+	IDStrategy IDStrategy
+}
+
+// ParseEditFlags loads the config file and parses the changelog file of the commands that edit an existing changelog file
+func ParseEditFlags(cmd *cobra.Command) (*EditFlags, error) {
+	configPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return nil, err
+	}
+
+	err = LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading config file '%s': %v", configPath, err)
+	}
+
+	file, err := ParseFileFlag(cmd, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if file == "" {
+		return nil, fmt.Errorf("No changelog file specified. Use the '--file' flag or the 'file' key of the config file")
+	}
+
+	if _, err := os.Stat(file); err != nil {
+		return nil, fmt.Errorf("Error reading changelog file '%s': %v", file, err)
+	}
+
+	backup, err := GetConfigFlagBool(cmd, "backup")
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := GetConfigTags()
+	if err != nil {
+		return nil, err
+	}
+
+	idStrategy, err := ParseIDStrategy(viper.GetString("id-strategy"))
+	if err != nil {
+		return nil, err
+	}
+
+	changelogFile, err := LockChangelogFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EditFlags{
+		ChangelogFile: changelogFile,
+		Backup:        backup,
+		Tags:          tags,
+		IDStrategy:    idStrategy,
+	}, nil
+}
+
+func findEntryIndex(entries []models.ChangelogEntry, version string) (int, error) {
+	index := FindChangelogEntry(entries, version)
+	if index == -1 {
+		return -1, fmt.Errorf("Version '%s' not found in the changelog file", version)
+	}
+	return index, nil
+}
+
+// FindChangelogChange returns the index of the change with the given ID, or -1 if there is none
+func FindChangelogChange(entry models.ChangelogEntry, id string) int {
+	_, index, found := lo.FindIndexOf(entry.Changes, func(change models.ChangelogChange) bool {
+		return change.ID == id
+	})
+	if !found {
+		return -1
+	}
+	return index
+}
+
+func findChangeIndex(entries []models.ChangelogEntry, version, id string) (int, int, error) {
+	entryIndex, err := findEntryIndex(entries, version)
+	if err != nil {
+		return -1, -1, err
+	}
+	changeIndex := FindChangelogChange(entries[entryIndex], id)
+	if changeIndex == -1 {
+		ids := lo.Map(entries[entryIndex].Changes, func(change models.ChangelogChange, _ int) string {
+			return change.ID
+		})
+		return -1, -1, fmt.Errorf("Change '%s' not found in version '%s'. Available changes are: %s", id, version, strings.Join(ids, ", "))
+	}
+	return entryIndex, changeIndex, nil
+}
+
+// cloneEntries copies the entries with their changes, highlights, variants and translations so they can be edited without
+// changing the original entries
+func cloneEntries(entries []models.ChangelogEntry) []models.ChangelogEntry {
+	return lo.Map(entries, func(entry models.ChangelogEntry, _ int) models.ChangelogEntry {
+		entry.Changes = append([]models.ChangelogChange{}, entry.Changes...)
+		entry.Highlights = slices.Clone(entry.Highlights)
+		if entry.Variants != nil {
+			entry.Variants = lo.MapValues(entry.Variants, func(changes []models.ChangelogChange, _ string) []models.ChangelogChange {
+				return slices.Clone(changes)
+			})
+		}
+		if entry.Translations != nil {
+			entry.Translations = lo.MapValues(entry.Translations, func(translations []models.ChangelogTranslation, _ string) []models.ChangelogTranslation {
+				return slices.Clone(translations)
+			})
+		}
+		return entry
+	})
+}
+
+// removeChangeReferences removes the highlight, the audience variants and the translations of the change with the given
+// ID from the entry. Audiences and languages left without changes are removed.
+func removeChangeReferences(entry *models.ChangelogEntry, id string) {
+	entry.Highlights = lo.Without(entry.Highlights, id)
+	for audience, changes := range entry.Variants {
+		entry.Variants[audience] = lo.Reject(changes, func(change models.ChangelogChange, _ int) bool { return change.ID == id })
+		if len(entry.Variants[audience]) == 0 {
+			delete(entry.Variants, audience)
+		}
+	}
+	for language, translations := range entry.Translations {
+		entry.Translations[language] = lo.Reject(translations, func(translation models.ChangelogTranslation, _ int) bool { return translation.ID == id })
+		if len(entry.Translations[language]) == 0 {
+			delete(entry.Translations, language)
+		}
+	}
+}
+
+// renameChangeReferences changes the ID of the highlight, the audience variants and the translations of a change
+func renameChangeReferences(entry *models.ChangelogEntry, id, newID string) {
+	for i := range entry.Highlights {
+		if entry.Highlights[i] == id {
+			entry.Highlights[i] = newID
+		}
+	}
+	for _, changes := range entry.Variants {
+		for i := range changes {
+			if changes[i].ID == id {
+				changes[i].ID = newID
+			}
+		}
+	}
+	for _, translations := range entry.Translations {
+		for i := range translations {
+			if translations[i].ID == id {
+				translations[i].ID = newID
+			}
+		}
+	}
+}
+
+// findChangeVersion returns the version of the entry that has a change with the given ID, or "" if there is none
+func findChangeVersion(entries []models.ChangelogEntry, id string) string {
+	entry, found := lo.Find(entries, func(entry models.ChangelogEntry) bool {
+		return FindChangelogChange(entry, id) != -1
+	})
+	return lo.Ternary(found, entry.Version, "")
+}
+
+// RemoveChangelogEntry removes the entry with the given version
+func RemoveChangelogEntry(entries []models.ChangelogEntry, version string) ([]models.ChangelogEntry, error) {
+	index, err := findEntryIndex(entries, version)
+	if err != nil {
+		return nil, err
+	}
+	updated := cloneEntries(entries)
+	return append(updated[:index], updated[index+1:]...), nil
+}
+
+// RemoveChangelogChange removes the change with the given ID from the entry with the given version, along with its
+// highlight, audience variants and translations
+func RemoveChangelogChange(entries []models.ChangelogEntry, version, id string) ([]models.ChangelogEntry, error) {
+	entryIndex, changeIndex, err := findChangeIndex(entries, version, id)
+	if err != nil {
+		return nil, err
+	}
+	updated := cloneEntries(entries)
+	changes := updated[entryIndex].Changes
+	updated[entryIndex].Changes = append(changes[:changeIndex], changes[changeIndex+1:]...)
+	removeChangeReferences(&updated[entryIndex], id)
+	return updated, nil
+}
+
+// MoveChangelogChange moves the change with the given ID to the end of the changes of the target version. Its audience
+// variants and translations are moved with it, except the variant for the audience of the target entry, and it is no
+// longer highlighted.
+func MoveChangelogChange(entries []models.ChangelogEntry, version, id, targetVersion string) ([]models.ChangelogEntry, error) {
+	entryIndex, changeIndex, err := findChangeIndex(entries, version, id)
+	if err != nil {
+		return nil, err
+	}
+	targetIndex, err := findEntryIndex(entries, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+	if targetIndex == entryIndex {
+		return nil, fmt.Errorf("Change '%s' is already in version '%s'", id, targetVersion)
+	}
+	if FindChangelogChange(entries[targetIndex], id) != -1 {
+		return nil, fmt.Errorf("Version '%s' already has a change with ID '%s'", targetVersion, id)
+	}
+
+	source := entries[entryIndex]
+	updated, err := RemoveChangelogChange(entries, version, id)
+	if err != nil {
+		return nil, err
+	}
+	target := &updated[targetIndex]
+	target.Changes = append(target.Changes, source.Changes[changeIndex])
+	for audience, changes := range source.Variants {
+		variant, found := lo.Find(changes, func(change models.ChangelogChange) bool { return change.ID == id })
+		if !found || audience == target.Audience {
+			continue
+		}
+		if target.Variants == nil {
+			target.Variants = map[string][]models.ChangelogChange{}
+		}
+		target.Variants[audience] = append(target.Variants[audience], variant)
+	}
+	for language, translations := range source.Translations {
+		translation, found := lo.Find(translations, func(translation models.ChangelogTranslation) bool { return translation.ID == id })
+		if !found {
+			continue
+		}
+		if target.Translations == nil {
+			target.Translations = map[string][]models.ChangelogTranslation{}
+		}
+		target.Translations[language] = append(target.Translations[language], translation)
+	}
+	return updated, nil
+}
+
+// RetagChangelogChange adds and removes tags of the change with the given ID
+func RetagChangelogChange(entries []models.ChangelogEntry, version, id string, add, remove []string) ([]models.ChangelogEntry, error) {
+	entryIndex, changeIndex, err := findChangeIndex(entries, version, id)
+	if err != nil {
+		return nil, err
+	}
+	updated := cloneEntries(entries)
+	change := &updated[entryIndex].Changes[changeIndex]
+	tags := lo.Without(change.Tags, remove...)
+	change.Tags = lo.Uniq(append(tags, add...))
+	if len(change.Tags) == 0 {
+		return nil, fmt.Errorf("Change '%s' must have at least one tag", id)
+	}
+	return updated, nil
+}
+
+// ReplaceChangelogChange replaces the change with the given ID, validating the new change. If the ID changes, the new ID
+// must not be used by any change of the file, and the highlight, audience variants and translations of the change are
+// renamed.
+func ReplaceChangelogChange(entries []models.ChangelogEntry, version, id string, change models.ChangelogChange, allowedTags []string) ([]models.ChangelogEntry, error) {
+	entryIndex, changeIndex, err := findChangeIndex(entries, version, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ValidateChangelogChange(change, allowedTags)
+	if err != nil {
+		return nil, err
+	}
+	if change.ID != id {
+		if usedBy := findChangeVersion(entries, change.ID); usedBy != "" {
+			return nil, fmt.Errorf("Version '%s' already has a change with ID '%s'", usedBy, change.ID)
+		}
+	}
+
+	updated := cloneEntries(entries)
+	updated[entryIndex].Changes[changeIndex] = change
+	if change.ID != id {
+		renameChangeReferences(&updated[entryIndex], id, change.ID)
+	}
+	return updated, nil
+}
+
+// ValidateChangelogChange checks the required fields and tags of a change
+func ValidateChangelogChange(change models.ChangelogChange, allowedTags []string) error {
+	if strings.TrimSpace(change.ID) == "" {
+		return fmt.Errorf("'id' is required")
+	}
+	if strings.TrimSpace(change.Title) == "" {
+		return fmt.Errorf("'title' is required")
+	}
+	if len(lo.Compact(change.Commits)) == 0 {
+		return fmt.Errorf("'commits' must have at least one commit")
+	}
+	if len(change.Tags) == 0 {
+		return fmt.Errorf("'tags' must have at least one tag")
+	}
+	for _, tag := range change.Tags {
+		if len(allowedTags) > 0 && !lo.Contains(allowedTags, tag) {
+			return fmt.Errorf("Unknown tag '%s'. Allowed tags are: %s", tag, strings.Join(allowedTags, ", "))
+		}
+	}
+	if change.Importance != "" && !lo.Contains(models.ImportanceLevels, change.Importance) {
+		return fmt.Errorf("Invalid importance '%s'. Use one of: %s", change.Importance, strings.Join(models.ImportanceLevels, ", "))
+	}
+	return nil
+}
+
+// MarshalChangelogChangeYAML encodes a change as YAML with the same field names and order as the JSON format
+func MarshalChangelogChangeYAML(change models.ChangelogChange) ([]byte, error) {
+	node, err := yamlNodeFromJSON(change)
+	if err != nil {
+		return nil, err
+	}
+	return encodeYAML(node)
+}
+
+func UnmarshalChangelogChangeYAML(contents []byte) (models.ChangelogChange, error) {
+	var change models.ChangelogChange
+	generic, err := decodeGenericChangelog(YAMLFormat, contents)
+	if err != nil {
+		return change, err
+	}
+	if _, ok := generic.(map[string]any); !ok {
+		return change, fmt.Errorf("expected a mapping with the fields of the change")
+	}
+	data, err := json.Marshal(generic)
+	if err != nil {
+		return change, err
+	}
+	err = json.Unmarshal(data, &change)
+	return change, err
+}
+
+// EditInEditor opens the contents in the user's editor ($VISUAL or $EDITOR) and returns the saved contents
+func EditInEditor(name string, contents []byte) ([]byte, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	file, err := os.CreateTemp("", "chlog-*-"+name)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(contents)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error writing temporary file: %v", err)
+	}
+
+	// The editor can include arguments (e.g. "code --wait")
+	args := append(strings.Fields(editor), file.Name())
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Error running editor '%s': %v", editor, err)
+	}
+
+	return os.ReadFile(file.Name())
+}
+
+// FindChangelogChangeByCommits returns the first change of the entry with the same commits (in any order), or nil if there is none
+func FindChangelogChangeByCommits(entry models.ChangelogEntry, commits []string) *models.ChangelogChange {
+	change, found := lo.Find(entry.Changes, func(change models.ChangelogChange) bool {
+		return len(change.Commits) > 0 && lo.ElementsMatch(change.Commits, commits)
+	})
+	if !found {
+		return nil
+	}
+	return &change
+}
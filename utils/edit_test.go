@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+)
+
+func testEditEntries() []models.ChangelogEntry {
+	change := func(id string) models.ChangelogChange {
+		return models.ChangelogChange{ID: id, Title: id, Commits: []string{id + "-commit"}, Tags: []string{"fix"}}
+	}
+	return []models.ChangelogEntry{
+		{
+			Version:    "1.1.0",
+			Audience:   "developer",
+			Changes:    []models.ChangelogChange{change("a"), change("b")},
+			Highlights: []string{"b", "a"},
+			Variants: map[string][]models.ChangelogChange{
+				"end-user": {change("a"), change("b")},
+				"internal": {change("b")},
+			},
+			Translations: map[string][]models.ChangelogTranslation{
+				"de": {{ID: "a", Title: "A"}, {ID: "b", Title: "B"}},
+				"fr": {{ID: "b", Title: "B"}},
+			},
+		},
+		{
+			Version:  "1.0.0",
+			Audience: "internal",
+			Changes:  []models.ChangelogChange{change("c")},
+		},
+	}
+}
+
+func TestRemoveChangelogChange(t *testing.T) {
+	entries := testEditEntries()
+	updated, err := RemoveChangelogChange(entries, "1.1.0", "b")
+	if err != nil {
+		t.Fatalf("RemoveChangelogChange() error: %v", err)
+	}
+
+	entry := updated[0]
+	if len(entry.Changes) != 1 || entry.Changes[0].ID != "a" {
+		t.Errorf("changes = %v, want only a", entry.Changes)
+	}
+	if !reflect.DeepEqual(entry.Highlights, []string{"a"}) {
+		t.Errorf("highlights = %v, want [a]", entry.Highlights)
+	}
+	if _, exists := entry.Variants["internal"]; exists || len(entry.Variants["end-user"]) != 1 {
+		t.Errorf("variants = %v, want only a for end-user", entry.Variants)
+	}
+	if _, exists := entry.Translations["fr"]; exists || len(entry.Translations["de"]) != 1 {
+		t.Errorf("translations = %v, want only a in de", entry.Translations)
+	}
+	if !reflect.DeepEqual(entries, testEditEntries()) {
+		t.Error("RemoveChangelogChange() changed the original entries")
+	}
+}
+
+func TestMoveChangelogChange(t *testing.T) {
+	updated, err := MoveChangelogChange(testEditEntries(), "1.1.0", "b", "1.0.0")
+	if err != nil {
+		t.Fatalf("MoveChangelogChange() error: %v", err)
+	}
+
+	source, target := updated[0], updated[1]
+	if FindChangelogChange(source, "b") != -1 || FindChangelogChange(target, "b") != 1 {
+		t.Errorf("change b was not moved: %v, %v", source.Changes, target.Changes)
+	}
+	if !reflect.DeepEqual(source.Highlights, []string{"a"}) || len(target.Highlights) != 0 {
+		t.Errorf("highlights = %v, %v, want [a] and none", source.Highlights, target.Highlights)
+	}
+	// The target entry is written for the internal audience, so only the end-user variant is moved
+	if len(target.Variants) != 1 || len(target.Variants["end-user"]) != 1 || target.Variants["end-user"][0].ID != "b" {
+		t.Errorf("target variants = %v, want b for end-user", target.Variants)
+	}
+	if len(target.Translations["de"]) != 1 || len(target.Translations["fr"]) != 1 {
+		t.Errorf("target translations = %v, want b in de and fr", target.Translations)
+	}
+}
+
+func TestMoveChangelogChangeErrors(t *testing.T) {
+	tests := []struct {
+		name                       string
+		version, id, targetVersion string
+	}{
+		{"same version", "1.1.0", "a", "1.1.0"},
+		{"unknown change", "1.1.0", "c", "1.0.0"},
+		{"unknown target", "1.1.0", "a", "2.0.0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := MoveChangelogChange(testEditEntries(), test.version, test.id, test.targetVersion); err == nil {
+				t.Error("MoveChangelogChange(): expected an error")
+			}
+		})
+	}
+}
+
+func TestReplaceChangelogChange(t *testing.T) {
+	replacement := func(id string) models.ChangelogChange {
+		return models.ChangelogChange{ID: id, Title: "Renamed", Commits: []string{"b-commit"}, Tags: []string{"fix"}}
+	}
+	tests := []struct {
+		name    string
+		change  models.ChangelogChange
+		wantErr bool
+	}{
+		{"same ID", replacement("b"), false},
+		{"new ID", replacement("renamed"), false},
+		{"ID of the same entry", replacement("a"), true},
+		{"ID of another entry", replacement("c"), true},
+		{"no commits", models.ChangelogChange{ID: "b", Title: "B", Tags: []string{"fix"}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated, err := ReplaceChangelogChange(testEditEntries(), "1.1.0", "b", test.change, []string{"fix"})
+			if test.wantErr {
+				if err == nil {
+					t.Error("ReplaceChangelogChange(): expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReplaceChangelogChange() error: %v", err)
+			}
+
+			entry := updated[0]
+			id := test.change.ID
+			if entry.Changes[1].Title != "Renamed" || entry.Highlights[0] != id {
+				t.Errorf("change = %v, highlights = %v", entry.Changes[1], entry.Highlights)
+			}
+			if entry.Variants["internal"][0].ID != id || entry.Translations["fr"][0].ID != id {
+				t.Errorf("variants = %v, translations = %v, want them renamed to %s", entry.Variants, entry.Translations, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// lockTimeout is how long to wait for another process to release a lock. Locks are held from reading the changelog
+	// file to writing it, which includes the LLM requests of e.g. chlog generate.
+	lockTimeout = 10 * time.Minute
+	// staleLockAge is the age after which a lock file is considered left behind by a crashed process
+	staleLockAge = 2 * time.Minute
+	// lockRefreshInterval is how often the modification time of a held lock file is refreshed, so it does not become stale
+	lockRefreshInterval = 30 * time.Second
+)
+
+// lockFile takes an advisory lock on path by creating a <path>.lock file, waiting for other processes holding the lock.
+// The returned function releases the lock.
+func lockFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = lock.WriteString(strconv.Itoa(os.Getpid()))
+			owned, _ := lock.Stat()
+			_ = lock.Close()
+
+			done := make(chan struct{})
+			go func() {
+				ticker := time.NewTicker(lockRefreshInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case now := <-ticker.C:
+						_ = os.Chtimes(lockPath, now, now)
+					}
+				}
+			}()
+			return func() {
+				close(done)
+				// Only remove the lock file if it is still ours, in case it was taken over as stale
+				if info, err := os.Stat(lockPath); err == nil && owned != nil && os.SameFile(info, owned) {
+					_ = os.Remove(lockPath)
+				}
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("Error locking file '%s': %v", path, err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			removeStaleLock(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Timed out waiting for the lock on '%s'. If no other chlog command is running, remove '%s'", path, lockPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// removeStaleLock removes a stale lock file. Other processes may find the same stale lock (or take a new lock in the
+// meantime), so the lock file is first renamed to a name unique to this process and only removed if it is still stale.
+// A fresh lock is put back.
+func removeStaleLock(lockPath string) {
+	stalePath := fmt.Sprintf("%s.stale-%d-%d", lockPath, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(lockPath, stalePath); err != nil {
+		return
+	}
+	if info, err := os.Stat(stalePath); err == nil && time.Since(info.ModTime()) <= staleLockAge {
+		_ = os.Link(stalePath, lockPath)
+	}
+	_ = os.Remove(stalePath)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and renames it to path,
+// so path either has its previous or its new contents, even if the process crashes while writing.
+// The permissions of an existing file are kept.
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
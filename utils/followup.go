@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+)
+
+// FollowUpOptions are the options of the follow-up requests made for some changes of a generated entry (e.g. the
+// upgrade guides of breaking changes)
+type FollowUpOptions struct {
+	AIClient   ai.AIClient
+	Model      string
+	StyleGuide string
+	// Language is the locale code of the language of the entry
+	Language string
+}
+
+type FollowUpResult struct {
+	Generated    int
+	InputTokens  int
+	OutputTokens int
+	// Errors are the errors of the changes whose follow-up request failed
+	Errors []error
+}
+
+// generateFollowUps calls generate for each change of the entry that needs a follow-up request, with a request per
+// change. The changes of the variants that need one are not sent a request: share copies the result of the change of
+// the entry with the same commits to them.
+func generateFollowUps(
+	entry *models.ChangelogEntry,
+	needs func(change models.ChangelogChange) bool,
+	generate func(change *models.ChangelogChange) (inputTokens int, outputTokens int, err error),
+	share func(from models.ChangelogChange, to *models.ChangelogChange),
+) FollowUpResult {
+	var result FollowUpResult
+	for i, change := range entry.Changes {
+		if !needs(change) {
+			continue
+		}
+		inputTokens, outputTokens, err := generate(&entry.Changes[i])
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		result.Generated++
+		result.InputTokens += inputTokens
+		result.OutputTokens += outputTokens
+	}
+
+	for _, changes := range entry.Variants {
+		for i, change := range changes {
+			if !needs(change) {
+				continue
+			}
+			if same := FindChangelogChangeByCommits(*entry, change.Commits); same != nil {
+				share(*same, &changes[i])
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,582 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+type ChangelogFormat string
+
+const (
+	JSONFormat ChangelogFormat = "json"
+	YAMLFormat ChangelogFormat = "yaml"
+	TOMLFormat ChangelogFormat = "toml"
+)
+
+// ChangelogFormatFromPath detects the changelog file format from the file extension.
+// Unknown extensions are treated as JSON to stay compatible with older versions.
+func ChangelogFormatFromPath(path string) ChangelogFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return YAMLFormat
+	case ".toml":
+		return TOMLFormat
+	default:
+		return JSONFormat
+	}
+}
+
+// emptyChangelogContents returns the contents used when creating a new changelog file.
+// TOML documents must be tables, so they always store the entries under the "entries" key.
+func emptyChangelogContents(format ChangelogFormat) []byte {
+	switch format {
+	case YAMLFormat:
+		return []byte("[]\n")
+	case TOMLFormat:
+		return []byte("entries = []\n")
+	default:
+		return []byte("[]")
+	}
+}
+
+// changelogDocument is the format independent representation of a changelog file.
+// Keys holds the top-level keys in file order and is nil when the file is a bare array of entries.
+type changelogDocument struct {
+	Keys    []string
+	Fields  map[string]any
+	Entries []models.ChangelogEntry
+}
+
+func (d *changelogDocument) hasEntriesKey() bool {
+	return d.Keys != nil
+}
+
+// toGeneric converts a value to its generic JSON representation (maps, slices and scalars)
+// so that it can be encoded with the same field names in every format.
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// tomlCompatible prepares a generic JSON value for the TOML encoder. Null values are removed since they cannot be
+// represented in TOML and whole numbers are converted to integers so they are not written as floats.
+func tomlCompatible(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			if item == nil {
+				delete(value, k)
+				continue
+			}
+			value[k] = tomlCompatible(item)
+		}
+		return value
+	case []any:
+		result := make([]any, 0, len(value))
+		for _, item := range value {
+			if item != nil {
+				result = append(result, tomlCompatible(item))
+			}
+		}
+		return result
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+			return int64(value)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+func entriesFromGeneric(generic []any) ([]models.ChangelogEntry, error) {
+	entries := []models.ChangelogEntry{}
+	if generic == nil {
+		return entries, nil
+	}
+	data, err := json.Marshal(generic)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// decodeChangelogDocument decodes the contents of a changelog file in the given format.
+func decodeChangelogDocument(format ChangelogFormat, contents []byte) (*changelogDocument, error) {
+	if len(bytes.TrimSpace(contents)) == 0 {
+		if format == TOMLFormat {
+			return &changelogDocument{Keys: []string{"entries"}, Fields: map[string]any{}, Entries: []models.ChangelogEntry{}}, nil
+		}
+		return &changelogDocument{Entries: []models.ChangelogEntry{}}, nil
+	}
+
+	switch format {
+	case YAMLFormat:
+		return decodeYAMLDocument(contents)
+	case TOMLFormat:
+		return decodeTOMLDocument(contents)
+	default:
+		return decodeJSONDocument(contents)
+	}
+}
+
+func newChangelogDocument(keys []string, fields map[string]any) (*changelogDocument, error) {
+	rawEntries, exists := fields["entries"]
+	if !exists {
+		return nil, fmt.Errorf("missing 'entries' key")
+	}
+	delete(fields, "entries")
+	var generic []any
+	if rawEntries != nil {
+		var ok bool
+		generic, ok = rawEntries.([]any)
+		if !ok {
+			return nil, fmt.Errorf("'entries' must be an array")
+		}
+	}
+	entries, err := entriesFromGeneric(generic)
+	if err != nil {
+		return nil, err
+	}
+	return &changelogDocument{Keys: keys, Fields: fields, Entries: entries}, nil
+}
+
+func newEntriesDocument(generic []any) (*changelogDocument, error) {
+	entries, err := entriesFromGeneric(generic)
+	if err != nil {
+		return nil, err
+	}
+	return &changelogDocument{Entries: entries}, nil
+}
+
+func decodeJSONDocument(contents []byte) (*changelogDocument, error) {
+	var generic any
+	if err := json.Unmarshal(contents, &generic); err != nil {
+		return nil, err
+	}
+	switch value := generic.(type) {
+	case []any:
+		return newEntriesDocument(value)
+	case map[string]any:
+		keys, err := jsonObjectKeys(contents)
+		if err != nil {
+			return nil, err
+		}
+		return newChangelogDocument(keys, value)
+	default:
+		return nil, fmt.Errorf("expected an array of entries or an object with an 'entries' key")
+	}
+}
+
+// jsonObjectKeys returns the top-level keys of a JSON object in the order they appear.
+func jsonObjectKeys(contents []byte) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, token.(string))
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// keepYAMLTimestampsAsStrings marks untagged timestamp values (e.g. date: 2025-05-15) as strings
+// so they are decoded as written instead of being converted to time.Time
+func keepYAMLTimestampsAsStrings(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!timestamp" && node.Style&yaml.TaggedStyle == 0 {
+		node.Tag = "!!str"
+	}
+	for _, child := range node.Content {
+		keepYAMLTimestampsAsStrings(child)
+	}
+}
+
+func decodeYAMLDocument(contents []byte) (*changelogDocument, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(contents, &node); err != nil {
+		return nil, err
+	}
+	keepYAMLTimestampsAsStrings(&node)
+	var generic any
+	if err := node.Decode(&generic); err != nil {
+		return nil, err
+	}
+	// Normalize YAML specific types (e.g. integer keys) to their JSON equivalents
+	generic, err := toGeneric(generic)
+	if err != nil {
+		return nil, err
+	}
+	switch value := generic.(type) {
+	case []any:
+		return newEntriesDocument(value)
+	case map[string]any:
+		var keys []string
+		root := node.Content[0]
+		for i := 0; i < len(root.Content); i += 2 {
+			keys = append(keys, root.Content[i].Value)
+		}
+		return newChangelogDocument(keys, value)
+	default:
+		return nil, fmt.Errorf("expected a list of entries or a mapping with an 'entries' key")
+	}
+}
+
+func decodeTOMLDocument(contents []byte) (*changelogDocument, error) {
+	var raw map[string]any
+	if err := toml.Unmarshal(contents, &raw); err != nil {
+		return nil, err
+	}
+	generic, err := toGeneric(raw)
+	if err != nil {
+		return nil, err
+	}
+	fields := generic.(map[string]any)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return newChangelogDocument(keys, fields)
+}
+
+// encodeChangelogDocument encodes a changelog document in the given format, keeping the key order of the document.
+func encodeChangelogDocument(format ChangelogFormat, doc *changelogDocument) ([]byte, error) {
+	entries := doc.Entries
+	if entries == nil {
+		entries = []models.ChangelogEntry{}
+	}
+
+	if !doc.hasEntriesKey() {
+		switch format {
+		case YAMLFormat:
+			node, err := yamlNodeFromJSON(entries)
+			if err != nil {
+				return nil, err
+			}
+			return encodeYAML(node)
+		case TOMLFormat:
+			// TOML does not support top-level arrays
+			tomlEntries, err := tomlEntriesValue(entries)
+			if err != nil {
+				return nil, err
+			}
+			return toml.Marshal(map[string]any{"entries": tomlEntries})
+		default:
+			return json.MarshalIndent(entries, "", "  ")
+		}
+	}
+
+	keys := doc.Keys
+	if !lo.Contains(keys, "entries") {
+		keys = append(keys, "entries")
+	}
+	valueOf := func(key string) any {
+		if key == "entries" {
+			return entries
+		}
+		return doc.Fields[key]
+	}
+
+	switch format {
+	case YAMLFormat:
+		root := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range keys {
+			value, err := yamlNodeFromJSON(valueOf(key))
+			if err != nil {
+				return nil, err
+			}
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+		}
+		return encodeYAML(root)
+	case TOMLFormat:
+		fields := make(map[string]any, len(keys))
+		for _, key := range keys {
+			if key == "entries" {
+				tomlEntries, err := tomlEntriesValue(entries)
+				if err != nil {
+					return nil, err
+				}
+				fields[key] = tomlEntries
+				continue
+			}
+			fields[key] = tomlCompatible(doc.Fields[key])
+		}
+		return toml.Marshal(fields)
+	default:
+		var buf bytes.Buffer
+		buf.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			keyJSON, err := json.Marshal(key)
+			if err != nil {
+				return nil, err
+			}
+			valueJSON, err := json.Marshal(valueOf(key))
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(keyJSON)
+			buf.WriteString(":")
+			buf.Write(valueJSON)
+		}
+		buf.WriteString("}")
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, buf.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		return pretty.Bytes(), nil
+	}
+}
+
+// yamlNodeFromJSON converts a value to a YAML node through its JSON encoding, so the YAML output has the same
+// field names and order as the JSON output (including any extra fields of the entries)
+func yamlNodeFromJSON(v any) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	resetYAMLStyle(node)
+	return node, nil
+}
+
+// resetYAMLStyle clears the JSON flow and quoting styles so the node is encoded as block style YAML
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// tomlEntriesValue returns the value used to encode the entries in TOML. The TOML encoder cannot encode the extra
+// fields of the entries, so entries with extra fields are encoded as maps (which sorts their keys).
+func tomlEntriesValue(entries []models.ChangelogEntry) (any, error) {
+	if !lo.SomeBy(entries, models.ChangelogEntry.HasExtraFields) {
+		return entries, nil
+	}
+	generic, err := toGeneric(entries)
+	if err != nil {
+		return nil, err
+	}
+	return tomlCompatible(generic), nil
+}
+
+func encodeYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// replaceYAMLEntries replaces the entries of a YAML changelog while keeping the rest of the document
+// (key order and comments) intact.
+func replaceYAMLEntries(contents []byte, entries []models.ChangelogEntry) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(contents, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping with an 'entries' key")
+	}
+	root := node.Content[0]
+	value, err := yamlNodeFromJSON(entries)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(root.Content); i += 2 {
+		if root.Content[i].Value == "entries" {
+			root.Content[i+1] = value
+			return encodeYAML(&node)
+		}
+	}
+	root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "entries"}, value)
+	return encodeYAML(&node)
+}
+
+// MarshalChangelogFile encodes a changelog file (e.g. a struct with metadata and entries) in the format
+// matching the extension of path, keeping the field order of the JSON encoding.
+func MarshalChangelogFile(path string, file any) ([]byte, error) {
+	data, err := json.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeJSONDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return encodeChangelogDocument(ChangelogFormatFromPath(path), doc)
+}
+
+// ConvertChangelogFile converts the changelog file at src to the format matching the extension of dst.
+// Top-level metadata and any extra fields in the entries are kept as is.
+func ConvertChangelogFile(src, dst string) error {
+	contents, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("Error reading file '%s': %v", src, err)
+	}
+
+	srcFormat := ChangelogFormatFromPath(src)
+	doc, err := decodeChangelogDocument(srcFormat, contents)
+	if err != nil {
+		return fmt.Errorf("Changelog file '%s' is not valid %s: %v", src, strings.ToUpper(string(srcFormat)), err)
+	}
+	dstFormat := ChangelogFormatFromPath(dst)
+	converted, err := encodeChangelogDocument(dstFormat, doc)
+	if err != nil {
+		return fmt.Errorf("Error marshalling changelog to %s: %v", strings.ToUpper(string(dstFormat)), err)
+	}
+
+	err = os.WriteFile(dst, converted, 0644)
+	if err != nil {
+		return fmt.Errorf("Error writing changelog file '%s': %v", dst, err)
+	}
+	return nil
+}
+
+// decodeGenericChangelog decodes the contents of a changelog file into its generic JSON representation
+func decodeGenericChangelog(format ChangelogFormat, contents []byte) (any, error) {
+	var generic any
+	var err error
+	switch format {
+	case YAMLFormat:
+		var node yaml.Node
+		if err = yaml.Unmarshal(contents, &node); err == nil {
+			keepYAMLTimestampsAsStrings(&node)
+			err = node.Decode(&generic)
+		}
+	case TOMLFormat:
+		var raw map[string]any
+		err = toml.Unmarshal(contents, &raw)
+		generic = raw
+	default:
+		err = json.Unmarshal(contents, &generic)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toGeneric(generic)
+}
+
+func joinValuePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// valueLines maps the paths of the values in a changelog file (e.g. "entries[0].changes[1].tags")
+// to the line they are on. Line numbers are not available for TOML files.
+func valueLines(format ChangelogFormat, contents []byte) map[string]int {
+	lines := map[string]int{}
+	switch format {
+	case YAMLFormat:
+		var node yaml.Node
+		if err := yaml.Unmarshal(contents, &node); err == nil && len(node.Content) > 0 {
+			yamlValueLines(node.Content[0], "", lines)
+		}
+	case JSONFormat:
+		jsonValueLines(contents, lines)
+	}
+	return lines
+}
+
+func yamlValueLines(node *yaml.Node, path string, lines map[string]int) {
+	lines[path] = node.Line
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childPath := joinValuePath(path, node.Content[i].Value)
+			yamlValueLines(node.Content[i+1], childPath, lines)
+			lines[childPath] = node.Content[i].Line
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			yamlValueLines(child, fmt.Sprintf("%s[%d]", path, i), lines)
+		}
+	}
+}
+
+func jsonValueLines(contents []byte, lines map[string]int) {
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	lineAt := func(offset int64) int {
+		return bytes.Count(contents[:offset], []byte("\n")) + 1
+	}
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if _, exists := lines[path]; !exists {
+			lines[path] = lineAt(decoder.InputOffset())
+		}
+
+		switch token {
+		case json.Delim('{'):
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				childPath := joinValuePath(path, key.(string))
+				lines[childPath] = lineAt(decoder.InputOffset())
+				if err := walk(childPath); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+		case json.Delim('['):
+			for i := 0; decoder.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+		}
+		return err
+	}
+	_ = walk("")
+}
@@ -4,29 +4,56 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ammar-ahmed22/chlog/ai"
 	"github.com/ammar-ahmed22/chlog/git"
-	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 type GenerateFlags struct {
-	From                       string
-	To                         string
-	Verbose                    bool
-	Provider                   string
-	Model                      string
-	Date                       string
-	APIKey                     string
-	Pretty                     bool
-	ExistingChangelog          []models.ChangelogEntry
-	ExistingChangelogInEntries bool
-	ExistingChangelogPath      string
+	From             string
+	To               string
+	Verbose          bool
+	Provider         string
+	Model            string
+	Date             string
+	APIKey           string
+	Pretty           bool
+	Backup           bool
+	OnConflict       ConflictStrategy
+	OnMissingCommits MissingCommitsStrategy
+	IDStrategy       IDStrategy
+	Authors          bool
+	// AuthorHandles maps lowercased emails to handles
+	AuthorHandles map[string]string
+	// Summary generates the summary and highlights of the entry, with up to Highlights highlights
+	Summary    bool
+	Highlights int
+	// Migrations generates an upgrade guide for each breaking or deprecated change
+	Migrations bool
+	// Security generates the advisory information of each security change
+	Security bool
+	// ReferencePatterns are the patterns of the issue references to attach to the changes, or nil if references are disabled
+	ReferencePatterns []git.ReferencePattern
+	Unreleased        bool
+	Review            bool
+	DryRun            bool
+	DryRunOutput      string
+	Tags              []ai.Tag
+	PromptTemplate    string
+	StyleGuide        string
+	// Audiences are the audiences to write the entry for, the first one is used for the changes of the entry and the others
+	// for its variants. It is empty if no audience is set.
+	Audiences             []ai.Audience
+	Language              string
+	ExistingChangelogFile *ParsedChangelogFile
 }
 
-func ParseGenerateFlags(cmd *cobra.Command) (*GenerateFlags, error) {
+func ParseGenerateFlags(cmd *cobra.Command) (flags *GenerateFlags, err error) {
 	configPath, err := cmd.Flags().GetString("config")
 	if err != nil {
 		return nil, err
@@ -60,6 +87,289 @@ func ParseGenerateFlags(cmd *cobra.Command) (*GenerateFlags, error) {
 		return nil, err
 	}
 
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return nil, err
+	}
+
+	dryRunOutput, err := cmd.Flags().GetString("dry-run-output")
+	if err != nil {
+		return nil, err
+	}
+
+	// The API key is not needed when the API is not called
+	aiFlags, err := parseAIFlags(cmd, !dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := cmd.Flags().GetString("date")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid date format '%s'. Use YYYY-MM-DD format", date)
+	}
+
+	pretty, err := GetConfigFlagBool(cmd, "pretty")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := ParseFileFlag(cmd, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := GetConfigFlagBool(cmd, "backup")
+	if err != nil {
+		return nil, err
+	}
+
+	onConflictValue, _, err := GetConfigFlagString(cmd, "on-conflict")
+	if err != nil {
+		return nil, err
+	}
+
+	onConflict, err := ParseConflictStrategy(onConflictValue)
+	if err != nil {
+		return nil, err
+	}
+
+	onMissingCommitsValue, _, err := GetConfigFlagString(cmd, "on-missing-commits")
+	if err != nil {
+		return nil, err
+	}
+
+	onMissingCommits, err := ParseMissingCommitsStrategy(onMissingCommitsValue)
+	if err != nil {
+		return nil, err
+	}
+
+	idStrategyValue, _, err := GetConfigFlagString(cmd, "id-strategy")
+	if err != nil {
+		return nil, err
+	}
+
+	idStrategy, err := ParseIDStrategy(idStrategyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	// A dry run has no side effects, so the file is not created if it does not exist
+	var existingChangelogFile *ParsedChangelogFile
+	if file != "" && dryRun {
+		existingChangelogFile, err = ReadChangelogFile(file)
+		if err != nil {
+			return nil, err
+		}
+	} else if file != "" {
+		// The file stays locked until the entry is written (see GenerateFlags.ExistingChangelogFile), unless the other
+		// flags are invalid
+		existingChangelogFile, err = LockChangelogFile(file)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				existingChangelogFile.Unlock()
+			}
+		}()
+	}
+
+	tags, err := GetConfigTags()
+	if err != nil {
+		return nil, err
+	}
+
+	promptTemplate, err := ParsePromptTemplate(cmd, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	audiences, err := ParseAudienceFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	language, _, err := GetConfigFlagString(cmd, "language")
+	if err != nil {
+		return nil, err
+	}
+	if language != "" {
+		if err := ai.ValidateLanguage(language); err != nil {
+			return nil, fmt.Errorf("Invalid language '%s'. Use a locale code (e.g. de, ja or pt-BR)", language)
+		}
+	}
+
+	authors, err := GetConfigFlagBool(cmd, "authors")
+	if err != nil {
+		return nil, err
+	}
+
+	authorHandles, err := GetConfigAuthorHandles()
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := GetConfigFlagBool(cmd, "references")
+	if err != nil {
+		return nil, err
+	}
+
+	var referencePatterns []git.ReferencePattern
+	if references {
+		referencePatterns, err = GetConfigReferencePatterns(ChangelogRepositoryInfo(existingChangelogFile).URL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	summary, err := GetConfigFlagBool(cmd, "summary")
+	if err != nil {
+		return nil, err
+	}
+
+	highlights := GetConfigHighlights()
+	if cmd.Flags().Changed("highlights") {
+		highlights, err = cmd.Flags().GetInt("highlights")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if highlights < 0 {
+		return nil, fmt.Errorf("Invalid number of highlights '%d'. Use 0 or a positive number", highlights)
+	}
+
+	// Upgrade guides and security advisories are generated unless they are turned off with the '--no-migrations' and
+	// '--no-security' flags or in the config file
+	noMigrations, err := cmd.Flags().GetBool("no-migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrations := !noMigrations && GetConfigMigrations()
+
+	noSecurity, err := cmd.Flags().GetBool("no-security")
+	if err != nil {
+		return nil, err
+	}
+	security := !noSecurity && GetConfigSecurity()
+
+	review, err := GetConfigFlagBool(cmd, "review")
+	if err != nil {
+		return nil, err
+	}
+
+	unreleased, err := cmd.Flags().GetBool("unreleased")
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue the Unreleased entry from where it started (e.g. the last release) unless '--from' is specified
+	if unreleased && existingChangelogFile != nil && !cmd.Flags().Changed("from") {
+		index := FindChangelogEntry(existingChangelogFile.Entries, UnreleasedVersion)
+		if index != -1 && existingChangelogFile.Entries[index].FromRef != "" {
+			from = existingChangelogFile.Entries[index].FromRef
+			err = git.IsValidRef(from)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid from reference '%s' of the %s entry. Use '--from, -f' to specify a valid Git reference", from, UnreleasedVersion)
+			}
+		}
+	}
+
+	return &GenerateFlags{
+		From:                  from,
+		To:                    to,
+		Verbose:               verbose,
+		Provider:              aiFlags.Provider,
+		Model:                 aiFlags.Model,
+		Date:                  date,
+		APIKey:                aiFlags.APIKey,
+		Pretty:                pretty,
+		Backup:                backup,
+		OnConflict:            onConflict,
+		OnMissingCommits:      onMissingCommits,
+		Authors:               authors,
+		AuthorHandles:         authorHandles,
+		Summary:               summary,
+		Highlights:            highlights,
+		Migrations:            migrations,
+		Security:              security,
+		ReferencePatterns:     referencePatterns,
+		IDStrategy:            idStrategy,
+		Unreleased:            unreleased,
+		Review:                review,
+		DryRun:                dryRun,
+		DryRunOutput:          dryRunOutput,
+		Tags:                  tags,
+		PromptTemplate:        promptTemplate,
+		StyleGuide:            GetConfigStyleGuide(),
+		Audiences:             audiences,
+		Language:              language,
+		ExistingChangelogFile: existingChangelogFile,
+	}, nil
+}
+
+// ParseAudienceFlag returns the audiences from the comma separated '--audience' flag or the 'audience' key of the config file
+// (a name or a list of names). The config file must already be loaded.
+func ParseAudienceFlag(cmd *cobra.Command) ([]ai.Audience, error) {
+	value, err := cmd.Flags().GetString("audience")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if value != "" {
+		names = strings.Split(value, ",")
+	} else if viper.IsSet("audience") {
+		switch configValue := viper.Get("audience").(type) {
+		case string:
+			names = strings.Split(configValue, ",")
+		case []any:
+			names = lo.Map(configValue, func(item any, _ int) string {
+				return fmt.Sprint(item)
+			})
+		default:
+			return nil, fmt.Errorf("Invalid 'audience' in config file. Expected an audience name or a list of names")
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	available, err := GetConfigAudiences()
+	if err != nil {
+		return nil, err
+	}
+
+	audiences := []ai.Audience{}
+	for _, name := range names {
+		audience, ok := ai.FindAudience(available, name)
+		if !ok {
+			return nil, fmt.Errorf("Invalid audience '%s'. Available audiences are: %s", strings.TrimSpace(name), strings.Join(ai.AudienceNames(available), ", "))
+		}
+		if !lo.ContainsBy(audiences, func(existing ai.Audience) bool { return existing.Name == audience.Name }) {
+			audiences = append(audiences, audience)
+		}
+	}
+	return audiences, nil
+}
+
+type AIFlags struct {
+	Provider string
+	Model    string
+	APIKey   string
+}
+
+// ParseAIFlags parses the provider, model and API key flags (falling back to the config file and environment variables).
+// The config file must already be loaded.
+func ParseAIFlags(cmd *cobra.Command) (*AIFlags, error) {
+	return parseAIFlags(cmd, true)
+}
+
+func parseAIFlags(cmd *cobra.Command, requireAPIKey bool) (*AIFlags, error) {
 	provider, _, err := GetConfigFlagString(cmd, "provider")
 	if err != nil {
 		return nil, err
@@ -92,62 +402,56 @@ func ParseGenerateFlags(cmd *cobra.Command) (*GenerateFlags, error) {
 	if apiKey == "" {
 		envVar := ai.ProviderEnvVarMap[provider]
 		value, ok := os.LookupEnv(envVar)
-		if !ok {
+		if !ok && requireAPIKey {
 			return nil, fmt.Errorf("API key for provider '%s' is required. Set it using the '--apiKey' flag or the environment variable '%s'", provider, envVar)
 		}
 		apiKey = value
 	}
 
-	date, err := cmd.Flags().GetString("date")
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = time.Parse("2006-01-02", date)
-	if err != nil {
-		return nil, fmt.Errorf("Invalid date format '%s'. Use YYYY-MM-DD format", date)
-	}
-
-	pretty, err := GetConfigFlagBool(cmd, "pretty")
-	if err != nil {
-		return nil, err
-	}
+	return &AIFlags{
+		Provider: provider,
+		Model:    model,
+		APIKey:   apiKey,
+	}, nil
+}
 
+// ParseFileFlag returns the changelog file path from the '--file' flag or the config file.
+// Paths from the config file are resolved relative to the config file.
+func ParseFileFlag(cmd *cobra.Command, configPath string) (string, error) {
 	file, fileFromConfig, err := GetConfigFlagString(cmd, "file")
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	var existingChangelog []models.ChangelogEntry
-	var existingChangelogInEntries bool
-	if file != "" {
-		if fileFromConfig {
-			// Join the config path with the file path
-			configDir := filepath.Dir(configPath)
-			joined := filepath.Join(configDir, file)
-			absPath, err := filepath.Abs(joined)
-			if err != nil {
-				return nil, fmt.Errorf("Error getting absolute path for file '%s': %v", file, err)
-			}
-			file = absPath
-		}
-		existingChangelog, existingChangelogInEntries, err = ParseAndValidateChangelogFile(file)
+	if file != "" && fileFromConfig {
+		// Join the config path with the file path
+		configDir := filepath.Dir(configPath)
+		joined := filepath.Join(configDir, file)
+		absPath, err := filepath.Abs(joined)
 		if err != nil {
-			return nil, err
+			return "", fmt.Errorf("Error getting absolute path for file '%s': %v", file, err)
 		}
+		file = absPath
 	}
+	return file, nil
+}
 
-	return &GenerateFlags{
-		From:                       from,
-		To:                         to,
-		Verbose:                    verbose,
-		Provider:                   provider,
-		Model:                      model,
-		Date:                       date,
-		APIKey:                     apiKey,
-		Pretty:                     pretty,
-		ExistingChangelog:          existingChangelog,
-		ExistingChangelogInEntries: existingChangelogInEntries,
-		ExistingChangelogPath:      file,
-	}, nil
+// FollowUpRequests describes the requests that may be sent after the entry is generated with the flags (e.g. an upgrade
+// guide per breaking change). How many are sent depends on the generated changes, so they are not part of the dry run
+// estimate.
+func FollowUpRequests(flags *GenerateFlags) []string {
+	requests := []string{}
+	if flags.OnMissingCommits == MissingCommitsRetry {
+		requests = append(requests, "one retry if commits are not referenced by any generated change")
+	}
+	if flags.Migrations {
+		requests = append(requests, "one upgrade guide per breaking or deprecated change")
+	}
+	if flags.Security {
+		requests = append(requests, "one security advisory per security change")
+	}
+	if flags.Summary {
+		requests = append(requests, "one summary of the entry")
+	}
+	return requests
 }
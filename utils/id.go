@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// IDStrategy is how the IDs of new changes are derived
+type IDStrategy string
+
+const (
+	// IDSlug derives the ID from the title (e.g. add-dark-mode), with a numeric suffix if it is already used (e.g. add-dark-mode-2)
+	IDSlug IDStrategy = "slug"
+	// IDHash derives the ID from the version and the commits of the change, so it does not change when the wording does
+	IDHash IDStrategy = "hash"
+)
+
+var IDStrategies = []IDStrategy{IDSlug, IDHash}
+
+// maxSlugLength is the maximum length of slug IDs, before the numeric suffix
+const maxSlugLength = 40
+
+// ParseIDStrategy parses the ID strategy, an empty value defaults to IDSlug
+func ParseIDStrategy(value string) (IDStrategy, error) {
+	if value == "" {
+		return IDSlug, nil
+	}
+	for _, strategy := range IDStrategies {
+		if string(strategy) == value {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid ID strategy '%s'. Valid strategies are: %s", value, strings.Join(lo.Map(IDStrategies, func(strategy IDStrategy, _ int) string {
+		return string(strategy)
+	}), ", "))
+}
+
+// ChangeID returns the ID of the change for the strategy, which is not necessarily unique (see AssignChangeIDs)
+func ChangeID(strategy IDStrategy, version string, change models.ChangelogChange) string {
+	if strategy == IDHash && len(change.Commits) > 0 {
+		commits := lo.Map(change.Commits, func(commit string, _ int) string {
+			return strings.ToLower(strings.TrimSpace(commit))
+		})
+		slices.Sort(commits)
+		sum := sha256.Sum256([]byte(version + "\n" + strings.Join(commits, "\n")))
+		return "c-" + hex.EncodeToString(sum[:])[:12]
+	}
+
+	id := TruncatedKebabCase(change.Title, maxSlugLength)
+	if id == "" {
+		return "change"
+	}
+	return id
+}
+
+// ChangelogChangeIDs returns the IDs used by the changes (and variants) of the entries, except for the entry of the
+// excluded version (e.g. an entry that is being replaced)
+func ChangelogChangeIDs(entries []models.ChangelogEntry, excludedVersion string) map[string]bool {
+	used := map[string]bool{}
+	for _, entry := range entries {
+		if excludedVersion != "" && entry.Version == excludedVersion {
+			continue
+		}
+		for _, change := range entry.Changes {
+			used[change.ID] = true
+		}
+		for _, changes := range entry.Variants {
+			for _, change := range changes {
+				used[change.ID] = true
+			}
+		}
+	}
+	delete(used, "")
+	return used
+}
+
+// UniqueID returns the ID, or the ID with the first numeric suffix that is not used (e.g. add-dark-mode-2), and marks it as used
+func UniqueID(id string, used map[string]bool) string {
+	unique := id
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", id, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// AssignChangeIDs sets the ID of each change for the strategy, adding a numeric suffix to IDs that are already used.
+// The used IDs are updated with the assigned IDs.
+func AssignChangeIDs(changes []models.ChangelogChange, version string, strategy IDStrategy, used map[string]bool) {
+	for i, change := range changes {
+		changes[i].ID = UniqueID(ChangeID(strategy, version, change), used)
+	}
+}
+
+// AssignVariantIDs sets the ID of each change of the variants of the entry to the ID of the change of the entry with the
+// same commits, so the same change has the same ID for every audience. The other changes of the variants get a new ID
+// (see AssignChangeIDs). The IDs of the changes of the entry must already be assigned.
+func AssignVariantIDs(entry *models.ChangelogEntry, version string, strategy IDStrategy, used map[string]bool) {
+	for _, audience := range slices.Sorted(maps.Keys(entry.Variants)) {
+		changes := entry.Variants[audience]
+		variantIDs := map[string]bool{}
+		for i, change := range changes {
+			if primary := FindChangelogChangeByCommits(*entry, change.Commits); primary != nil && !variantIDs[primary.ID] {
+				changes[i].ID = primary.ID
+			} else {
+				changes[i].ID = UniqueID(ChangeID(strategy, version, change), used)
+			}
+			variantIDs[changes[i].ID] = true
+		}
+	}
+}
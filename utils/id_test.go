@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+func TestUniqueID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		used []string
+		want string
+	}{
+		{"unused", "add-dark-mode", nil, "add-dark-mode"},
+		{"used", "add-dark-mode", []string{"add-dark-mode"}, "add-dark-mode-2"},
+		{"suffix used", "add-dark-mode", []string{"add-dark-mode", "add-dark-mode-2"}, "add-dark-mode-3"},
+		{"only suffix used", "add-dark-mode", []string{"add-dark-mode-2"}, "add-dark-mode"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			used := lo.SliceToMap(test.used, func(id string) (string, bool) { return id, true })
+			if got := UniqueID(test.id, used); got != test.want {
+				t.Errorf("UniqueID(%q) = %q, want %q", test.id, got, test.want)
+			}
+			if !used[test.want] {
+				t.Errorf("UniqueID(%q) did not mark %q as used", test.id, test.want)
+			}
+		})
+	}
+}
+
+func TestChangeID(t *testing.T) {
+	change := models.ChangelogChange{Title: "Add dark mode", Commits: []string{"B", " a "}}
+	reworded := models.ChangelogChange{Title: "Support a dark theme", Commits: []string{"a", "b"}}
+
+	tests := []struct {
+		name     string
+		strategy IDStrategy
+		version  string
+		change   models.ChangelogChange
+		want     string
+	}{
+		{"slug", IDSlug, "1.0.0", change, "add-dark-mode"},
+		{"long title", IDSlug, "1.0.0", models.ChangelogChange{Title: strings.Repeat("word ", 20)}, strings.TrimSuffix(strings.Repeat("word-", 8), "-")},
+		{"empty title", IDSlug, "1.0.0", models.ChangelogChange{Title: "!"}, "change"},
+		{"hash without commits", IDHash, "1.0.0", models.ChangelogChange{Title: "Add dark mode"}, "add-dark-mode"},
+		{"hash of reworded change", IDHash, "1.0.0", reworded, ChangeID(IDHash, "1.0.0", change)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ChangeID(test.strategy, test.version, test.change); got != test.want {
+				t.Errorf("ChangeID() = %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	hash := ChangeID(IDHash, "1.0.0", change)
+	if !strings.HasPrefix(hash, "c-") || len(hash) != 14 {
+		t.Errorf("ChangeID() = %q, want c- and 12 hex digits", hash)
+	}
+	if ChangeID(IDHash, "1.1.0", change) == hash {
+		t.Error("ChangeID() is the same for another version")
+	}
+}
+
+func TestAssignVariantIDs(t *testing.T) {
+	entry := models.ChangelogEntry{
+		Changes: []models.ChangelogChange{
+			{ID: "add-dark-mode", Title: "Add dark mode", Commits: []string{"a"}},
+		},
+		Variants: map[string][]models.ChangelogChange{
+			"developer": {
+				{Title: "Add a theme setting", Commits: []string{"a"}},
+				{Title: "Add dark mode", Commits: []string{"b"}},
+			},
+		},
+	}
+	used := map[string]bool{"add-dark-mode": true}
+	AssignVariantIDs(&entry, "1.0.0", IDSlug, used)
+
+	got := lo.Map(entry.Variants["developer"], func(change models.ChangelogChange, _ int) string { return change.ID })
+	if want := []string{"add-dark-mode", "add-dark-mode-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AssignVariantIDs() = %v, want %v", got, want)
+	}
+}
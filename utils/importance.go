@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// ParseImportance returns the importance level of the value (case-insensitive), or an empty string if the value is empty
+func ParseImportance(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value != "" && !lo.Contains(models.ImportanceLevels, value) {
+		return "", fmt.Errorf("Invalid importance '%s'. Use one of: %s", value, strings.Join(models.ImportanceLevels, ", "))
+	}
+	return value, nil
+}
+
+// FilterImportance returns the changes that are at least as important as the minimum importance. Changes without an
+// importance (e.g. written before importance was added) are always kept.
+func FilterImportance(changes []models.ChangelogChange, minImportance string) []models.ChangelogChange {
+	minimum := slices.Index(models.ImportanceLevels, minImportance)
+	if minimum <= 0 {
+		return changes
+	}
+	return lo.Filter(changes, func(change models.ChangelogChange, _ int) bool {
+		return change.Importance == "" || slices.Index(models.ImportanceLevels, change.Importance) >= minimum
+	})
+}
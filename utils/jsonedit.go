@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/models"
+)
+
+// jsonArraySpan is the location of a JSON array and its elements in a file
+type jsonArraySpan struct {
+	Open     int
+	Close    int
+	Elements [][2]int
+}
+
+// findJSONEntriesArray finds the entries array of a JSON changelog file, which is either the top-level array
+// or the array under the top-level "entries" key
+func findJSONEntriesArray(contents []byte, entriesKey bool) (*jsonArraySpan, error) {
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	if entriesKey {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if token != json.Delim('{') {
+			return nil, fmt.Errorf("expected an object with an 'entries' key")
+		}
+		found := false
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			if key == "entries" {
+				found = true
+				break
+			}
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return nil, err
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("missing 'entries' key")
+		}
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('[') {
+		return nil, fmt.Errorf("expected an array of entries")
+	}
+	span := &jsonArraySpan{Open: int(decoder.InputOffset()) - 1}
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+		end := int(decoder.InputOffset())
+		span.Elements = append(span.Elements, [2]int{end - len(raw), end})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	span.Close = int(decoder.InputOffset()) - 1
+	return span, nil
+}
+
+// lineIndent returns the leading whitespace of the line containing offset
+func lineIndent(contents []byte, offset int) string {
+	start := bytes.LastIndexByte(contents[:offset], '\n') + 1
+	end := start
+	for end < len(contents) && (contents[end] == ' ' || contents[end] == '\t') {
+		end++
+	}
+	return string(contents[start:end])
+}
+
+// detectIndentUnit returns the indentation of the first indented line, defaulting to two spaces
+func detectIndentUnit(contents []byte) string {
+	for _, line := range bytes.Split(contents, []byte("\n")) {
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) > 0 && len(trimmed) < len(line) {
+			return string(line[:len(line)-len(trimmed)])
+		}
+	}
+	return "  "
+}
+
+func canonicalEntryJSON(entry models.ChangelogEntry) (string, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// spliceJSONEntries updates the entries array of a JSON changelog file with minimal edits. Entries that did not change
+// keep their exact bytes, new or modified entries are formatted with the indentation style of the file and everything
+// outside of the entries array is left untouched.
+func spliceJSONEntries(contents []byte, entriesKey bool, entries []models.ChangelogEntry) ([]byte, error) {
+	span, err := findJSONEntriesArray(contents, entriesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the existing entries by their normalized JSON so unchanged entries can be reused as is
+	existing := map[string][]int{}
+	for i, element := range span.Elements {
+		var entry models.ChangelogEntry
+		if err := json.Unmarshal(contents[element[0]:element[1]], &entry); err != nil {
+			return nil, err
+		}
+		key, err := canonicalEntryJSON(entry)
+		if err != nil {
+			return nil, err
+		}
+		existing[key] = append(existing[key], i)
+	}
+
+	closingIndent := lineIndent(contents, span.Open)
+	var leading, separator, trailing, elementIndent, indentUnit string
+	var compact bool
+	if len(span.Elements) > 0 {
+		first, last := span.Elements[0], span.Elements[len(span.Elements)-1]
+		leading = string(contents[span.Open+1 : first[0]])
+		trailing = string(contents[last[1]:span.Close])
+		compact = !strings.Contains(leading, "\n")
+		if len(span.Elements) > 1 {
+			separator = string(contents[first[1]:span.Elements[1][0]])
+		} else {
+			separator = "," + leading
+		}
+		elementIndent = lineIndent(contents, first[0])
+		if strings.HasPrefix(elementIndent, closingIndent) && len(elementIndent) > len(closingIndent) {
+			indentUnit = elementIndent[len(closingIndent):]
+		} else {
+			indentUnit = detectIndentUnit(contents)
+		}
+	} else {
+		// Keep single line files on a single line, everything else is indented like the rest of the file
+		compact = entriesKey && !bytes.Contains(bytes.TrimSpace(contents), []byte("\n"))
+		indentUnit = detectIndentUnit(contents)
+		elementIndent = closingIndent + indentUnit
+		if compact {
+			separator = ","
+		} else {
+			leading = "\n" + elementIndent
+			separator = ",\n" + elementIndent
+			trailing = "\n" + closingIndent
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.Write(contents[:span.Open+1])
+	if len(entries) > 0 {
+		buf.WriteString(leading)
+	}
+	for i, entry := range entries {
+		if i > 0 {
+			buf.WriteString(separator)
+		}
+
+		key, err := canonicalEntryJSON(entry)
+		if err != nil {
+			return nil, err
+		}
+		if indices := existing[key]; len(indices) > 0 {
+			element := span.Elements[indices[0]]
+			existing[key] = indices[1:]
+			buf.Write(contents[element[0]:element[1]])
+			continue
+		}
+
+		var formatted []byte
+		if compact {
+			formatted, err = json.Marshal(entry)
+		} else {
+			formatted, err = json.MarshalIndent(entry, elementIndent, indentUnit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(formatted)
+	}
+	if len(entries) > 0 {
+		buf.WriteString(trailing)
+	}
+	buf.Write(contents[span.Close:])
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+)
+
+func TestSpliceJSONEntries(t *testing.T) {
+	newEntry := models.ChangelogEntry{Version: "1.1.0", Date: "2024-02-01", FromRef: "v1.0.0", ToRef: "v1.1.0", Changes: []models.ChangelogChange{}}
+	oldEntry := models.ChangelogEntry{Version: "1.0.0", Date: "2024-01-01", FromRef: "a", ToRef: "v1.0.0", Changes: []models.ChangelogChange{}}
+
+	tests := []struct {
+		name       string
+		contents   string
+		entriesKey bool
+		entries    []models.ChangelogEntry
+		want       string
+	}{
+		{
+			name:     "prepend to top-level array",
+			contents: "[\n  {\"to_ref\": \"v1.0.0\", \"version\": \"1.0.0\", \"date\": \"2024-01-01\", \"from_ref\": \"a\", \"changes\": []}\n]\n",
+			entries:  []models.ChangelogEntry{newEntry, oldEntry},
+			want: "[\n  {\n    \"version\": \"1.1.0\",\n    \"date\": \"2024-02-01\",\n    \"from_ref\": \"v1.0.0\",\n    \"to_ref\": \"v1.1.0\",\n    \"changes\": []\n  },\n" +
+				"  {\"to_ref\": \"v1.0.0\", \"version\": \"1.0.0\", \"date\": \"2024-01-01\", \"from_ref\": \"a\", \"changes\": []}\n]\n",
+		},
+		{
+			name:       "entries key with tabs",
+			contents:   "{\n\t\"title\":   \"Changelog\",\n\t\"entries\": [\n\t\t{\"version\": \"1.0.0\", \"date\": \"2024-01-01\", \"from_ref\": \"a\", \"to_ref\": \"v1.0.0\", \"changes\": []}\n\t]\n}",
+			entriesKey: true,
+			entries:    []models.ChangelogEntry{newEntry, oldEntry},
+			want: "{\n\t\"title\":   \"Changelog\",\n\t\"entries\": [\n\t\t{\n\t\t\t\"version\": \"1.1.0\",\n\t\t\t\"date\": \"2024-02-01\",\n\t\t\t\"from_ref\": \"v1.0.0\",\n\t\t\t\"to_ref\": \"v1.1.0\",\n\t\t\t\"changes\": []\n\t\t},\n" +
+				"\t\t{\"version\": \"1.0.0\", \"date\": \"2024-01-01\", \"from_ref\": \"a\", \"to_ref\": \"v1.0.0\", \"changes\": []}\n\t]\n}",
+		},
+		{
+			name:       "empty single line file",
+			contents:   `{"title": "Changelog", "entries": []}`,
+			entriesKey: true,
+			entries:    []models.ChangelogEntry{newEntry},
+			want:       `{"title": "Changelog", "entries": [{"version":"1.1.0","date":"2024-02-01","from_ref":"v1.0.0","to_ref":"v1.1.0","changes":[]}]}`,
+		},
+		{
+			name:     "empty array",
+			contents: "[]\n",
+			entries:  []models.ChangelogEntry{newEntry},
+			want:     "[\n  {\n    \"version\": \"1.1.0\",\n    \"date\": \"2024-02-01\",\n    \"from_ref\": \"v1.0.0\",\n    \"to_ref\": \"v1.1.0\",\n    \"changes\": []\n  }\n]\n",
+		},
+		{
+			name:     "remove all entries",
+			contents: "[\n  {\"version\": \"1.0.0\", \"date\": \"2024-01-01\", \"from_ref\": \"a\", \"to_ref\": \"v1.0.0\", \"changes\": []}\n]\n",
+			entries:  []models.ChangelogEntry{},
+			want:     "[]\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := spliceJSONEntries([]byte(test.contents), test.entriesKey, test.entries)
+			if err != nil {
+				t.Fatalf("spliceJSONEntries() error: %v", err)
+			}
+			if string(got) != test.want {
+				t.Errorf("spliceJSONEntries() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSpliceJSONEntriesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		contents   string
+		entriesKey bool
+	}{
+		{"missing entries key", `{"title": "Changelog"}`, true},
+		{"entries key of an array", `[]`, true},
+		{"top-level object", `{"entries": []}`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := spliceJSONEntries([]byte(test.contents), test.entriesKey, nil); err == nil {
+				t.Error("spliceJSONEntries(): expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/invopop/jsonschema"
+	"github.com/samber/lo"
+)
+
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+type LintDiagnostic struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	Path     string       `json:"path"`
+	Line     int          `json:"line,omitempty"`
+}
+
+// LintRules describes the rules checked by LintChangelogFile
+var LintRules = map[string]string{
+	"schema":              "The changelog file must match the changelog file JSON schema",
+	"duplicate-version":   "Versions must be unique",
+	"version-order":       "Entries must be ordered from the most recent to the oldest version",
+	"invalid-date":        "Dates must be in YYYY-MM-DD format",
+	"duplicate-change-id": "Change IDs must be unique within an entry",
+	"shared-change-id":    "Change IDs should be unique across the changelog file, so anchors and feed GUIDs are reliable",
+	"missing-commits":     "Changes must reference at least one commit",
+	"unknown-commit":      "Commits must resolve in the Git repository",
+	"unknown-tag":         "Tags must be one of the allowed tags",
+	"unknown-highlight":   "Highlights must be IDs of changes of the entry",
+}
+
+type LintOptions struct {
+	// AllowedTags is the set of tags changes can use
+	AllowedTags []string
+	// CheckCommits checks that the commits of each change resolve in the current Git repository
+	CheckCommits bool
+}
+
+type linter struct {
+	options     LintOptions
+	lines       map[string]int
+	diagnostics []LintDiagnostic
+	commits     map[string]bool
+}
+
+func (l *linter) report(rule string, severity LintSeverity, path string, format string, args ...any) {
+	l.diagnostics = append(l.diagnostics, LintDiagnostic{
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		Path:     path,
+		Line:     l.lines[path],
+	})
+}
+
+// LintChangelogFile validates a changelog file against the changelog file schema and checks the semantic rules in LintRules.
+func LintChangelogFile(path string, options LintOptions) ([]LintDiagnostic, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading file '%s': %v", path, err)
+	}
+
+	format := ChangelogFormatFromPath(path)
+	l := &linter{
+		options: options,
+		lines:   valueLines(format, contents),
+		commits: map[string]bool{},
+	}
+
+	generic, err := decodeGenericChangelog(format, contents)
+	if err != nil {
+		l.report("schema", LintError, "", "File is not valid %s: %v", strings.ToUpper(string(format)), err)
+		return l.diagnostics, nil
+	}
+
+	l.validateSchema(models.ChangelogFileSchema, generic, "")
+
+	var rawEntries []any
+	entriesPath := ""
+	switch value := generic.(type) {
+	case []any:
+		rawEntries = value
+	case map[string]any:
+		rawEntries, _ = value["entries"].([]any)
+		entriesPath = "entries"
+	}
+
+	entries := make([]*models.ChangelogEntry, len(rawEntries))
+	for i, raw := range rawEntries {
+		// Entries that do not match the model are already reported by the schema validation
+		var entry models.ChangelogEntry
+		data, err := json.Marshal(raw)
+		if err == nil && json.Unmarshal(data, &entry) == nil {
+			entries[i] = &entry
+		}
+	}
+
+	l.lintEntries(entries, entriesPath)
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		return l.diagnostics[i].Line < l.diagnostics[j].Line
+	})
+	return l.diagnostics, nil
+}
+
+func (l *linter) validateSchema(schema *jsonschema.Schema, value any, path string) {
+	if len(schema.OneOf) > 0 {
+		for _, option := range schema.OneOf {
+			if schemaTypeMatches(option.Type, value) {
+				l.validateSchema(option, value, path)
+				return
+			}
+		}
+		types := lo.Map(schema.OneOf, func(option *jsonschema.Schema, _ int) string {
+			return option.Type
+		})
+		l.report("schema", LintError, path, "Expected %s, got %s", strings.Join(types, " or "), genericTypeName(value))
+		return
+	}
+
+	if schema.Type != "" && !schemaTypeMatches(schema.Type, value) {
+		l.report("schema", LintError, path, "Expected %s, got %s", schema.Type, genericTypeName(value))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !lo.ContainsBy(schema.Enum, func(option any) bool { return reflect.DeepEqual(option, value) }) {
+		l.report("schema", LintError, path, "Value %v is not one of %v", value, schema.Enum)
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, required := range schema.Required {
+			if _, ok := value[required]; !ok {
+				l.report("schema", LintError, path, "Missing required field '%s'", required)
+			}
+		}
+		keys := lo.Keys(value)
+		sort.Strings(keys)
+		for _, key := range keys {
+			var propertySchema *jsonschema.Schema
+			if schema.Properties != nil {
+				propertySchema, _ = schema.Properties.Get(key)
+			}
+			if propertySchema == nil {
+				propertySchema = schema.AdditionalProperties
+			}
+			if propertySchema == jsonschema.FalseSchema {
+				l.report("schema", LintError, joinValuePath(path, key), "Unknown field '%s'", key)
+				continue
+			}
+			if propertySchema != nil {
+				l.validateSchema(propertySchema, value[key], joinValuePath(path, key))
+			}
+		}
+	case []any:
+		if schema.Items != nil {
+			for i, item := range value {
+				l.validateSchema(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+func schemaTypeMatches(schemaType string, value any) bool {
+	switch schemaType {
+	case "":
+		return true
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	default:
+		return schemaType == genericTypeName(value)
+	}
+}
+
+func genericTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func (l *linter) lintEntries(entries []*models.ChangelogEntry, entriesPath string) {
+	versions := map[string]string{}
+	changeIDs := map[string]string{}
+	var previous *models.ChangelogEntry
+	for i, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		entryPath := fmt.Sprintf("%s[%d]", entriesPath, i)
+
+		if first, exists := versions[entry.Version]; exists {
+			l.report("duplicate-version", LintError, joinValuePath(entryPath, "version"), "Version '%s' is already used by %s", entry.Version, first)
+		} else {
+			versions[entry.Version] = entryPath
+		}
+
+		if entry.Version == UnreleasedVersion {
+			if i != 0 {
+				l.report("version-order", LintError, joinValuePath(entryPath, "version"), "The %s entry should be the first entry", UnreleasedVersion)
+			}
+		} else if entry.Date == "" {
+			l.report("invalid-date", LintWarning, joinValuePath(entryPath, "date"), "Version '%s' has no date", entry.Version)
+		} else if _, err := time.Parse("2006-01-02", entry.Date); err != nil {
+			l.report("invalid-date", LintError, joinValuePath(entryPath, "date"), "Invalid date '%s', use YYYY-MM-DD format", entry.Date)
+		}
+
+		if previous != nil && previous.Version != UnreleasedVersion && entry.Version != UnreleasedVersion {
+			l.lintOrder(previous, entry, entryPath)
+		}
+		previous = entry
+
+		l.lintChanges(entry, entryPath, changeIDs)
+	}
+}
+
+// lintOrder checks that the entry is not newer than the previous entry, in the order of SortChangelogEntries
+func (l *linter) lintOrder(previous, entry *models.ChangelogEntry, entryPath string) {
+	if CompareChangelogEntries(*previous, *entry) >= 0 {
+		return
+	}
+	if entryVersionKind(*previous) == otherVersion && entryVersionKind(*entry) == otherVersion {
+		l.report("version-order", LintError, joinValuePath(entryPath, "date"), "Version '%s' (%s) is more recent than the previous version '%s' (%s)", entry.Version, entry.Date, previous.Version, previous.Date)
+		return
+	}
+	l.report("version-order", LintError, joinValuePath(entryPath, "version"), "Version '%s' is newer than the previous version '%s'", entry.Version, previous.Version)
+}
+
+// lintChanges checks the changes of the entry. fileIDs maps the change IDs of the previous entries to their versions.
+func (l *linter) lintChanges(entry *models.ChangelogEntry, entryPath string, fileIDs map[string]string) {
+	// Entries without any commits (e.g. imported from a Markdown changelog) cannot be fixed, so only a warning is reported
+	missingCommitsSeverity := LintWarning
+	if lo.SomeBy(entry.Changes, func(change models.ChangelogChange) bool { return len(change.Commits) > 0 }) {
+		missingCommitsSeverity = LintError
+	}
+
+	ids := map[string]bool{}
+	for i, change := range entry.Changes {
+		changePath := fmt.Sprintf("%s.changes[%d]", entryPath, i)
+
+		if change.ID != "" {
+			if ids[change.ID] {
+				l.report("duplicate-change-id", LintError, joinValuePath(changePath, "id"), "Change ID '%s' is used more than once in version '%s'", change.ID, entry.Version)
+			} else if version, exists := fileIDs[change.ID]; exists {
+				l.report("shared-change-id", LintWarning, joinValuePath(changePath, "id"), "Change ID '%s' is also used in version '%s'", change.ID, version)
+			}
+			ids[change.ID] = true
+		}
+
+		if len(change.Commits) == 0 {
+			l.report("missing-commits", missingCommitsSeverity, joinValuePath(changePath, "commits"), "Change '%s' has no commits", change.Title)
+		}
+		for j, commit := range change.Commits {
+			commitPath := fmt.Sprintf("%s.commits[%d]", changePath, j)
+			if strings.TrimSpace(commit) == "" {
+				l.report("missing-commits", LintError, commitPath, "Empty commit hash")
+				continue
+			}
+			if l.options.CheckCommits && !l.commitExists(commit) {
+				l.report("unknown-commit", LintError, commitPath, "Commit '%s' does not exist in the repository", commit)
+			}
+		}
+
+		if len(l.options.AllowedTags) > 0 {
+			for j, tag := range change.Tags {
+				if !lo.Contains(l.options.AllowedTags, tag) {
+					l.report("unknown-tag", LintError, fmt.Sprintf("%s.tags[%d]", changePath, j), "Unknown tag '%s'. Allowed tags are: %s", tag, strings.Join(l.options.AllowedTags, ", "))
+				}
+			}
+		}
+	}
+
+	for i, id := range entry.Highlights {
+		if !ids[id] {
+			l.report("unknown-highlight", LintWarning, fmt.Sprintf("%s.highlights[%d]", entryPath, i), "Highlight '%s' is not the ID of a change of version '%s'", id, entry.Version)
+		}
+	}
+
+	for id := range ids {
+		if _, exists := fileIDs[id]; !exists {
+			fileIDs[id] = entry.Version
+		}
+	}
+}
+
+func (l *linter) commitExists(commit string) bool {
+	exists, checked := l.commits[commit]
+	if !checked {
+		exists = git.CommitExists(commit)
+		l.commits[commit] = exists
+	}
+	return exists
+}
+
+// CountLintDiagnostics returns the number of errors and warnings in the diagnostics
+func CountLintDiagnostics(diagnostics []LintDiagnostic) (errors int, warnings int) {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == LintError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// FormatLintSARIF formats the diagnostics as a SARIF 2.1.0 log (e.g. for GitHub code scanning)
+func FormatLintSARIF(path string, diagnostics []LintDiagnostic) ([]byte, error) {
+	ruleIDs := lo.Keys(LintRules)
+	sort.Strings(ruleIDs)
+	rules := lo.Map(ruleIDs, func(id string, _ int) map[string]any {
+		return map[string]any{
+			"id":               id,
+			"shortDescription": map[string]any{"text": LintRules[id]},
+		}
+	})
+
+	results := lo.Map(diagnostics, func(diagnostic LintDiagnostic, _ int) map[string]any {
+		physicalLocation := map[string]any{
+			"artifactLocation": map[string]any{"uri": path},
+		}
+		if diagnostic.Line > 0 {
+			physicalLocation["region"] = map[string]any{"startLine": diagnostic.Line}
+		}
+		location := map[string]any{"physicalLocation": physicalLocation}
+		if diagnostic.Path != "" {
+			location["logicalLocations"] = []map[string]any{{"fullyQualifiedName": diagnostic.Path}}
+		}
+		return map[string]any{
+			"ruleId":    diagnostic.Rule,
+			"level":     string(diagnostic.Severity),
+			"message":   map[string]any{"text": diagnostic.Message},
+			"locations": []map[string]any{location},
+		}
+	})
+
+	sarif := map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []map[string]any{
+			{
+				"tool": map[string]any{
+					"driver": map[string]any{
+						"name":           "chlog",
+						"informationUri": "https://github.com/ammar-ahmed22/chlog",
+						"rules":          rules,
+					},
+				},
+				"results": results,
+			},
+		},
+	}
+	return json.MarshalIndent(sarif, "", "  ")
+}
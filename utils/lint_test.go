@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+func testLintChange(id string, commits ...string) models.ChangelogChange {
+	return models.ChangelogChange{ID: id, Title: id, Description: id, Impact: id, Commits: append([]string{}, commits...), Tags: []string{"fix"}}
+}
+
+func testLintEntry(version, date string, changes ...models.ChangelogChange) models.ChangelogEntry {
+	return models.ChangelogEntry{Version: version, Date: date, FromRef: "a", ToRef: "b", Changes: changes}
+}
+
+func writeLintFile(t *testing.T, contents []byte) string {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.json")
+	if err := os.WriteFile(path, contents, 0644); err != nil {
+		t.Fatalf("Error writing changelog file: %v", err)
+	}
+	return path
+}
+
+func TestLintChangelogFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []models.ChangelogEntry
+		options LintOptions
+		want    []string
+	}{
+		{
+			name: "valid",
+			entries: []models.ChangelogEntry{
+				testLintEntry(UnreleasedVersion, "", testLintChange("a", "1")),
+				testLintEntry("1.1.0", "2024-02-01", testLintChange("b", "2")),
+				testLintEntry("1.0.0", "2024-01-01", testLintChange("c", "3")),
+			},
+			want: []string{},
+		},
+		{
+			name: "duplicate version",
+			entries: []models.ChangelogEntry{
+				testLintEntry("1.0.0", "2024-01-01", testLintChange("a", "1")),
+				testLintEntry("1.0.0", "2024-01-01", testLintChange("b", "2")),
+			},
+			want: []string{"duplicate-version error"},
+		},
+		{
+			name: "unreleased entry after a release",
+			entries: []models.ChangelogEntry{
+				testLintEntry("1.0.0", "2024-01-01", testLintChange("a", "1")),
+				testLintEntry(UnreleasedVersion, "", testLintChange("b", "2")),
+			},
+			want: []string{"version-order error"},
+		},
+		{
+			name: "older version first",
+			entries: []models.ChangelogEntry{
+				testLintEntry("1.0.0", "2024-01-01", testLintChange("a", "1")),
+				testLintEntry("2.0.0", "2024-02-01", testLintChange("b", "2")),
+			},
+			want: []string{"version-order error"},
+		},
+		{
+			name: "missing and invalid dates",
+			entries: []models.ChangelogEntry{
+				testLintEntry("1.1.0", "", testLintChange("a", "1")),
+				testLintEntry("1.0.0", "01/01/2024", testLintChange("b", "2")),
+			},
+			want: []string{"invalid-date warning", "invalid-date error"},
+		},
+		{
+			name: "duplicate and shared change IDs",
+			entries: []models.ChangelogEntry{
+				testLintEntry("1.1.0", "2024-02-01", testLintChange("a", "1"), testLintChange("a", "2")),
+				testLintEntry("1.0.0", "2024-01-01", testLintChange("a", "3")),
+			},
+			want: []string{"duplicate-change-id error", "shared-change-id warning"},
+		},
+		{
+			name: "missing commits",
+			entries: []models.ChangelogEntry{
+				testLintEntry("1.1.0", "2024-02-01", testLintChange("a", "1"), testLintChange("b"), testLintChange("c", " ")),
+				// The changes of imported entries have no commits at all
+				testLintEntry("1.0.0", "2024-01-01", testLintChange("d"), testLintChange("e")),
+			},
+			want: []string{"missing-commits error", "missing-commits error", "missing-commits warning", "missing-commits warning"},
+		},
+		{
+			name: "unknown tag",
+			entries: []models.ChangelogEntry{
+				testLintEntry("1.0.0", "2024-01-01", testLintChange("a", "1")),
+			},
+			options: LintOptions{AllowedTags: []string{"feature"}},
+			want:    []string{"unknown-tag error"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contents, err := json.MarshalIndent(models.ChangelogFile{Title: "Changelog", Entries: test.entries}, "", "  ")
+			if err != nil {
+				t.Fatalf("Error marshalling changelog file: %v", err)
+			}
+			diagnostics, err := LintChangelogFile(writeLintFile(t, contents), test.options)
+			if err != nil {
+				t.Fatalf("LintChangelogFile() error: %v", err)
+			}
+			got := lo.Map(diagnostics, func(diagnostic LintDiagnostic, _ int) string {
+				return diagnostic.Rule + " " + string(diagnostic.Severity)
+			})
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("LintChangelogFile() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLintChangelogFileHighlights(t *testing.T) {
+	entry := testLintEntry("1.0.0", "2024-01-01", testLintChange("a", "1"))
+	entry.Highlights = []string{"a", "missing"}
+	contents, _ := json.MarshalIndent([]models.ChangelogEntry{entry}, "", "  ")
+
+	diagnostics, err := LintChangelogFile(writeLintFile(t, contents), LintOptions{})
+	if err != nil {
+		t.Fatalf("LintChangelogFile() error: %v", err)
+	}
+	want := []LintDiagnostic{{
+		Rule:     "unknown-highlight",
+		Severity: LintWarning,
+		Message:  "Highlight 'missing' is not the ID of a change of version '1.0.0'",
+		Path:     "[0].highlights[1]",
+		Line:     23,
+	}}
+	if !reflect.DeepEqual(diagnostics, want) {
+		t.Errorf("LintChangelogFile() = %+v, want %+v", diagnostics, want)
+	}
+}
+
+func TestLintChangelogFileSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"invalid JSON", `{"entries": [`},
+		{"wrong type", `[{"version": 1, "date": "", "from_ref": "a", "to_ref": "b", "changes": []}]`},
+		{"missing property", `[{"version": "1.0.0", "date": "2024-01-01", "changes": []}]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diagnostics, err := LintChangelogFile(writeLintFile(t, []byte(test.contents)), LintOptions{})
+			if err != nil {
+				t.Fatalf("LintChangelogFile() error: %v", err)
+			}
+			if len(diagnostics) == 0 || !lo.EveryBy(diagnostics, func(diagnostic LintDiagnostic) bool {
+				return diagnostic.Rule == "schema" && diagnostic.Severity == LintError
+			}) {
+				t.Errorf("LintChangelogFile() = %+v, want schema errors", diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// MarkdownSectionTags maps Keep a Changelog section names (lowercased) to the default chlog tags, used for the sections
+// that are not named after one of the tags
+var MarkdownSectionTags = map[string]string{
+	"added":            "feature",
+	"new":              "feature",
+	"features":         "feature",
+	"changed":          "improvement",
+	"improved":         "improvement",
+	"improvements":     "improvement",
+	"deprecated":       "deprecation",
+	"removed":          "breaking",
+	"breaking":         "breaking",
+	"breaking changes": "breaking",
+	"fixed":            "fix",
+	"fixes":            "fix",
+	"bug fixes":        "fix",
+	"security":         "security",
+	"documentation":    "documentation",
+	"docs":             "documentation",
+}
+
+// DefaultMarkdownSectionTag is used for sections that are not in MarkdownSectionTags
+const DefaultMarkdownSectionTag = "improvement"
+
+var (
+	markdownVersionHeadingRegex = regexp.MustCompile(`^##\s+(.+?)\s*$`)
+	markdownSectionHeadingRegex = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+	markdownBulletRegex         = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	markdownLinkDefRegex        = regexp.MustCompile(`^\s*\[([^\]]+)\]:\s*(\S+)`)
+	markdownLinkRegex           = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownDateRegex           = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+	markdownVersionRegex        = regexp.MustCompile(`^\[?([^\]\s]+)\]?`)
+	markdownCompareRegex        = regexp.MustCompile(`/compare/(.+?)\.{2,3}(.+)$`)
+	markdownTagLinkRegex        = regexp.MustCompile(`/(?:releases/tag|tree)/(.+)$`)
+	markdownCommitRegex         = regexp.MustCompile(`(?:/commit/|\()([0-9a-f]{7,40})\b`)
+	markdownCommitRefRegex      = regexp.MustCompile(`\s*\(\s*\[?[0-9a-f]{7,40}\]?(\([^)]*\))?\s*\)`)
+)
+
+// ParseMarkdownChangelog parses a Keep a Changelog style Markdown changelog into changelog entries.
+// Versions are read from level 2 headings, tags from level 3 section headings and changes from the bullet items.
+// Compare links at the bottom of the file (e.g. "[1.1.0]: https://.../compare/v1.0.0...v1.1.0") are used for the refs.
+// The changes only get the allowed tags (see markdownSectionTag), an error is returned if a section has none.
+func ParseMarkdownChangelog(contents string, tags []string) ([]models.ChangelogEntry, error) {
+	lines := strings.Split(strings.ReplaceAll(contents, "\r\n", "\n"), "\n")
+
+	links := map[string]string{}
+	for _, line := range lines {
+		if match := markdownLinkDefRegex.FindStringSubmatch(line); match != nil {
+			links[strings.ToLower(match[1])] = match[2]
+		}
+	}
+
+	var entries []models.ChangelogEntry
+	var entry *models.ChangelogEntry
+	var change *models.ChangelogChange
+	section := ""
+	tag, tagErr := markdownSectionTag(section, tags)
+
+	flushChange := func() {
+		if entry != nil && change != nil {
+			entry.Changes = append(entry.Changes, finalizeMarkdownChange(*change))
+		}
+		change = nil
+	}
+	flushEntry := func() {
+		flushChange()
+		if entry != nil {
+			entries = append(entries, *entry)
+		}
+		entry = nil
+	}
+
+	for _, line := range lines {
+		if match := markdownSectionHeadingRegex.FindStringSubmatch(line); match != nil {
+			flushChange()
+			section = match[1]
+			tag, tagErr = markdownSectionTag(section, tags)
+			continue
+		}
+
+		if match := markdownVersionHeadingRegex.FindStringSubmatch(line); match != nil {
+			flushEntry()
+			entry = newMarkdownEntry(match[1], links)
+			section = ""
+			tag, tagErr = markdownSectionTag(section, tags)
+			continue
+		}
+
+		if entry == nil || markdownLinkDefRegex.MatchString(line) {
+			continue
+		}
+
+		if match := markdownBulletRegex.FindStringSubmatch(line); match != nil {
+			if len(match[1]) == 0 || change == nil {
+				if tagErr != nil {
+					return nil, fmt.Errorf("Invalid section '%s' of version '%s': %v", lo.Ternary(section != "", section, "(none)"), entry.Version, tagErr)
+				}
+				flushChange()
+				change = &models.ChangelogChange{
+					Description: strings.TrimSpace(match[2]),
+					Tags:        []string{tag},
+				}
+				continue
+			}
+			// Nested bullets are kept as part of the parent change's description
+			change.Description += "\n- " + strings.TrimSpace(match[2])
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if change != nil && line != trimmed {
+			// Continuation of a bullet item wrapped over multiple lines
+			change.Description += " " + trimmed
+		}
+	}
+	flushEntry()
+
+	return entries, nil
+}
+
+// markdownSectionTag returns the allowed tag with the name of the section (e.g. "### Fixed" for a fixed tag), or else the
+// tag of MarkdownSectionTags (DefaultMarkdownSectionTag for unknown sections) if it is allowed. Every tag is allowed if
+// there are no tags.
+func markdownSectionTag(section string, tags []string) (string, error) {
+	name := strings.TrimSpace(section)
+	if tag, found := lo.Find(tags, func(tag string) bool { return strings.EqualFold(tag, name) }); found && name != "" {
+		return tag, nil
+	}
+
+	fallback, ok := MarkdownSectionTags[strings.ToLower(name)]
+	if !ok {
+		fallback = DefaultMarkdownSectionTag
+	}
+	if len(tags) == 0 {
+		return fallback, nil
+	}
+	if tag, found := lo.Find(tags, func(tag string) bool { return strings.EqualFold(tag, fallback) }); found {
+		return tag, nil
+	}
+	return "", fmt.Errorf("no tag matches the section (allowed tags are: %s). Rename the section or add a tag for it to the 'tags' of the config file", strings.Join(tags, ", "))
+}
+
+func newMarkdownEntry(heading string, links map[string]string) *models.ChangelogEntry {
+	entry := &models.ChangelogEntry{Changes: []models.ChangelogChange{}}
+
+	var label string
+	if match := markdownVersionRegex.FindStringSubmatch(heading); match != nil {
+		label = match[1]
+	}
+	entry.Version = label
+	if len(label) > 1 && label[0] == 'v' && label[1] >= '0' && label[1] <= '9' {
+		entry.Version = label[1:]
+	}
+	entry.Date = markdownDateRegex.FindString(heading)
+
+	link, ok := links[strings.ToLower(label)]
+	if !ok {
+		return entry
+	}
+	if match := markdownCompareRegex.FindStringSubmatch(link); match != nil {
+		entry.FromRef = match[1]
+		entry.ToRef = match[2]
+	} else if match := markdownTagLinkRegex.FindStringSubmatch(link); match != nil {
+		entry.ToRef = match[1]
+	}
+	return entry
+}
+
+func finalizeMarkdownChange(change models.ChangelogChange) models.ChangelogChange {
+	change.Commits = []string{}
+	for _, match := range markdownCommitRegex.FindAllStringSubmatch(change.Description, -1) {
+		change.Commits = append(change.Commits, match[1])
+	}
+	change.Commits = lo.Uniq(change.Commits)
+
+	// Use the first sentence of the bullet (without links and commit references) as the title
+	title := markdownCommitRefRegex.ReplaceAllString(change.Description, "")
+	title = markdownLinkRegex.ReplaceAllString(title, "$1")
+	title = strings.SplitN(title, "\n", 2)[0]
+	if sentence, _, found := strings.Cut(title, ". "); found {
+		title = sentence
+	}
+	change.Title = strings.TrimSuffix(strings.TrimSpace(title), ".")
+	change.ID = TruncatedKebabCase(change.Title, 40)
+	return change
+}
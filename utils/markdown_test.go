@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testMarkdownChangelog = `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2024-02-01
+### Added
+- Add the render command. It renders Markdown ([abc1234](https://github.com/owner/repo/commit/abc1234))
+- Support YAML files
+  that span lines
+  - with nested details
+
+### Fixed
+- Fix a crash (def5678)
+
+## v1.0.0 - 2024-01-01
+### Security
+- Escape the output
+
+[1.1.0]: https://github.com/owner/repo/compare/v1.0.0...v1.1.0
+[1.0.0]: https://github.com/owner/repo/releases/tag/v1.0.0
+`
+
+func TestParseMarkdownChangelog(t *testing.T) {
+	entries, err := ParseMarkdownChangelog(testMarkdownChangelog, nil)
+	if err != nil {
+		t.Fatalf("ParseMarkdownChangelog() error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("ParseMarkdownChangelog() returned %d entries, want 3", len(entries))
+	}
+
+	unreleased, release, first := entries[0], entries[1], entries[2]
+	if unreleased.Version != "Unreleased" || len(unreleased.Changes) != 0 {
+		t.Errorf("unreleased entry = %+v", unreleased)
+	}
+	if release.Version != "1.1.0" || release.Date != "2024-02-01" || release.FromRef != "v1.0.0" || release.ToRef != "v1.1.0" {
+		t.Errorf("entry 1.1.0 = %s %s %s..%s", release.Version, release.Date, release.FromRef, release.ToRef)
+	}
+	if first.Version != "1.0.0" || first.ToRef != "" {
+		t.Errorf("entry 1.0.0 = %s with to_ref %q, want no link for the v1.0.0 heading", first.Version, first.ToRef)
+	}
+
+	tests := []struct {
+		index                  int
+		id, title, description string
+		commits, tags          []string
+	}{
+		{
+			0, "add-the-render-command", "Add the render command",
+			"Add the render command. It renders Markdown ([abc1234](https://github.com/owner/repo/commit/abc1234))",
+			[]string{"abc1234"}, []string{"feature"},
+		},
+		{
+			1, "support-yaml-files-that-span-lines", "Support YAML files that span lines",
+			"Support YAML files that span lines\n- with nested details",
+			[]string{}, []string{"feature"},
+		},
+		{2, "fix-a-crash", "Fix a crash", "Fix a crash (def5678)", []string{"def5678"}, []string{"fix"}},
+	}
+	for _, test := range tests {
+		change := release.Changes[test.index]
+		if change.ID != test.id || change.Title != test.title || change.Description != test.description {
+			t.Errorf("change %d = %q, %q, %q, want %q, %q, %q", test.index, change.ID, change.Title, change.Description, test.id, test.title, test.description)
+		}
+		if !reflect.DeepEqual(change.Commits, test.commits) || !reflect.DeepEqual(change.Tags, test.tags) {
+			t.Errorf("change %d commits, tags = %v, %v, want %v, %v", test.index, change.Commits, change.Tags, test.commits, test.tags)
+		}
+	}
+}
+
+func TestParseMarkdownChangelogDisallowedSection(t *testing.T) {
+	if _, err := ParseMarkdownChangelog(testMarkdownChangelog, []string{"feature", "fix"}); err == nil {
+		t.Error("ParseMarkdownChangelog(): expected an error for the Security section without a security tag")
+	}
+}
+
+func TestMarkdownSectionTag(t *testing.T) {
+	tests := []struct {
+		section string
+		tags    []string
+		want    string
+		wantErr bool
+	}{
+		{"Added", nil, "feature", false},
+		{"Bug Fixes", nil, "fix", false},
+		{"Removed", nil, "breaking", false},
+		{"Miscellaneous", nil, DefaultMarkdownSectionTag, false},
+		{"", nil, DefaultMarkdownSectionTag, false},
+		{"Performance", []string{"performance", "fix"}, "performance", false},
+		{"fixed", []string{"Fix"}, "Fix", false},
+		{"Added", []string{"fix"}, "", true},
+	}
+	for _, test := range tests {
+		got, err := markdownSectionTag(test.section, test.tags)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("markdownSectionTag(%q, %v) = %q, %v, want %q (error: %v)", test.section, test.tags, got, err, test.want, test.wantErr)
+		}
+	}
+}
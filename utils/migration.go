@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/spf13/viper"
+)
+
+// GetConfigMigrations returns the 'migrations' key of the config file, upgrade guides are generated if it is not set.
+// The config file must already be loaded.
+func GetConfigMigrations() bool {
+	return !viper.IsSet("migrations") || viper.GetBool("migrations")
+}
+
+// GenerateMigrations generates the upgrade guide of each breaking or deprecated change of the entry that does not have
+// one yet, with a request per change. The changes of the variants get the upgrade guide of the change with the same commits.
+func GenerateMigrations(entry *models.ChangelogEntry, options FollowUpOptions) FollowUpResult {
+	needs := func(change models.ChangelogChange) bool {
+		return ai.NeedsMigration(change) && change.Migration == nil
+	}
+	generate := func(change *models.ChangelogChange) (int, int, error) {
+		prompt, err := ai.BuildMigrationPrompt(*change, options.Language, options.StyleGuide)
+		if err == nil {
+			var response ai.GenerateMigrationResponse
+			response, err = options.AIClient.GenerateMigration(options.Model, prompt)
+			if err == nil {
+				change.Migration = &response.Migration
+				return response.InputTokens, response.OutputTokens, nil
+			}
+		}
+		return 0, 0, fmt.Errorf("Could not generate the upgrade guide of change '%s': %v", change.Title, err)
+	}
+	share := func(from models.ChangelogChange, to *models.ChangelogChange) {
+		to.Migration = from.Migration
+	}
+	return generateFollowUps(entry, needs, generate, share)
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// OSVSchemaVersion is the version of the OSV schema (https://ossf.github.io/osv-schema/) of the rendered advisories
+const OSVSchemaVersion = "1.6.0"
+
+// OSVAdvisory is an advisory in the Open Source Vulnerability format. The advisories rendered from the changelog are
+// stubs to review and complete (e.g. with the affected package and versions) before publishing.
+type OSVAdvisory struct {
+	SchemaVersion    string             `json:"schema_version"`
+	ID               string             `json:"id"`
+	Modified         string             `json:"modified,omitempty"`
+	Published        string             `json:"published,omitempty"`
+	Aliases          []string           `json:"aliases,omitempty"`
+	Summary          string             `json:"summary,omitempty"`
+	Details          string             `json:"details,omitempty"`
+	Affected         []OSVAffected      `json:"affected,omitempty"`
+	References       []OSVReference     `json:"references,omitempty"`
+	DatabaseSpecific OSVDatabaseDetails `json:"database_specific"`
+}
+
+type OSVAffected struct {
+	Ranges []OSVRange `json:"ranges"`
+}
+
+type OSVRange struct {
+	Type   string              `json:"type"`
+	Repo   string              `json:"repo"`
+	Events []map[string]string `json:"events"`
+}
+
+type OSVReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// OSVDatabaseDetails holds the advisory information that has no OSV field (the severity is an estimate, not a CVSS vector)
+type OSVDatabaseDetails struct {
+	Version string `json:"version"`
+	Change  string `json:"change"`
+	// PlaceholderID is true if the ID of the advisory is a CHLOG-<version>-<change ID> placeholder, which must be
+	// replaced with a real advisory ID (e.g. a CVE or GHSA ID) before publishing
+	PlaceholderID bool   `json:"placeholder_id,omitempty"`
+	Severity      string `json:"severity,omitempty"`
+	Component     string `json:"component,omitempty"`
+	// Merged are the other changes that reference the same advisory ID (e.g. a backport of the fix to another version)
+	Merged []OSVChange `json:"merged,omitempty"`
+}
+
+// OSVChange is a change of the changelog that an advisory was rendered from
+type OSVChange struct {
+	Version string `json:"version"`
+	Change  string `json:"change"`
+}
+
+// OSVAdvisories returns an advisory for each change of the entries with advisory information (see chlog generate).
+// The ID of the advisory is the first CVE or GHSA ID of the change, with the others as aliases, or a
+// CHLOG-<version>-<change ID> placeholder if the change has none (see OSVDatabaseDetails.PlaceholderID). Changes with
+// the same advisory ID are merged into one advisory (see mergeOSVAdvisory). The repository URL is used for the affected
+// Git range and, on GitHub, for the links to the fix commits. The range is fixed by the newest commit of the change in
+// the current Git repository, or by its last commit if they cannot be found. The published and modified timestamps are
+// left empty for undated entries.
+func OSVAdvisories(entries []models.ChangelogEntry, repositoryURL string) []OSVAdvisory {
+	advisories := []OSVAdvisory{}
+	indexes := map[string]int{}
+	githubURL := GitHubRepositoryURL(repositoryURL)
+	for _, entry := range entries {
+		date := osvTimestamp(entry.Date)
+		for _, change := range entry.Changes {
+			if change.Security == nil {
+				continue
+			}
+			security := change.Security
+
+			advisory := OSVAdvisory{
+				SchemaVersion: OSVSchemaVersion,
+				ID:            fmt.Sprintf("CHLOG-%s-%s", entry.Version, change.ID),
+				Modified:      date,
+				Published:     date,
+				Summary:       lo.Ternary(security.Summary != "", security.Summary, change.Title),
+				Details:       strings.TrimSpace(strings.Join(lo.Compact([]string{change.Description, change.Impact}), "\n\n")),
+				DatabaseSpecific: OSVDatabaseDetails{
+					Version:       entry.Version,
+					Change:        change.ID,
+					PlaceholderID: len(security.IDs) == 0,
+					Severity:      security.Severity,
+					Component:     security.Component,
+				},
+			}
+			if len(security.IDs) > 0 {
+				advisory.ID = security.IDs[0]
+				advisory.Aliases = security.IDs[1:]
+			}
+
+			for _, id := range security.IDs {
+				if url := advisoryURL(id); url != "" {
+					advisory.References = append(advisory.References, OSVReference{Type: "ADVISORY", URL: url})
+				}
+			}
+			if githubURL != "" {
+				for _, commit := range change.Commits {
+					advisory.References = append(advisory.References, OSVReference{Type: "FIX", URL: fmt.Sprintf("%s/commit/%s", githubURL, commit)})
+				}
+			}
+			if repositoryURL != "" && len(change.Commits) > 0 {
+				advisory.Affected = []OSVAffected{{Ranges: []OSVRange{{
+					Type: "GIT",
+					Repo: lo.Ternary(githubURL != "", githubURL, repositoryURL),
+					Events: []map[string]string{
+						{"introduced": "0"},
+						{"fixed": fixedCommit(change.Commits)},
+					},
+				}}}}
+			}
+			if index, exists := indexes[advisory.ID]; exists {
+				mergeOSVAdvisory(&advisories[index], advisory)
+				continue
+			}
+			indexes[advisory.ID] = len(advisories)
+			advisories = append(advisories, advisory)
+		}
+	}
+	return advisories
+}
+
+// mergeOSVAdvisory merges an advisory of another change with the same ID into the advisory. The summary and component
+// of the first change are kept, the other fields are combined: the affected ranges, references, aliases and details of
+// both, the earliest published and latest modified timestamps, and the most severe severity.
+func mergeOSVAdvisory(advisory *OSVAdvisory, other OSVAdvisory) {
+	advisory.Aliases = lo.Without(lo.Uniq(append(append([]string{}, advisory.Aliases...), other.Aliases...)), advisory.ID)
+	if other.Details != "" && !strings.Contains(advisory.Details, other.Details) {
+		advisory.Details = strings.TrimSpace(advisory.Details + "\n\n" + other.Details)
+	}
+	advisory.Affected = append(advisory.Affected, other.Affected...)
+	advisory.References = lo.UniqBy(append(advisory.References, other.References...), func(reference OSVReference) string {
+		return reference.URL
+	})
+	if other.Published != "" && (advisory.Published == "" || other.Published < advisory.Published) {
+		advisory.Published = other.Published
+	}
+	if other.Modified > advisory.Modified {
+		advisory.Modified = other.Modified
+	}
+
+	details := &advisory.DatabaseSpecific
+	if slices.Index(models.SeverityLevels, other.DatabaseSpecific.Severity) > slices.Index(models.SeverityLevels, details.Severity) {
+		details.Severity = other.DatabaseSpecific.Severity
+	}
+	if details.Component == "" {
+		details.Component = other.DatabaseSpecific.Component
+	}
+	details.Merged = append(details.Merged, OSVChange{Version: other.DatabaseSpecific.Version, Change: other.DatabaseSpecific.Change})
+}
+
+// fixedCommit returns the newest of the commits of a change (the commits are listed in no particular order), or the
+// last commit if they cannot be found in the current Git repository
+func fixedCommit(commits []string) string {
+	if newest, err := git.NewestCommit(commits); err == nil {
+		return newest
+	}
+	return commits[len(commits)-1]
+}
+
+// advisoryURL returns the URL of a CVE or GHSA ID from the AdvisoryPatterns, or an empty string if no pattern matches
+func advisoryURL(id string) string {
+	for _, pattern := range AdvisoryPatterns {
+		if pattern.Regex.MatchString(id) {
+			return strings.ReplaceAll(pattern.URL, "{id}", id)
+		}
+	}
+	return ""
+}
+
+// osvTimestamp returns the RFC 3339 timestamp of a YYYY-MM-DD date, or an empty string if the date cannot be parsed
+func osvTimestamp(date string) string {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ""
+	}
+	return parsed.UTC().Format(time.RFC3339)
+}
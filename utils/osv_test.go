@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+func testSecurityChange(id string, ids []string, severity string) models.ChangelogChange {
+	return models.ChangelogChange{
+		ID:          id,
+		Title:       id,
+		Description: id + " description",
+		Commits:     []string{id + "-commit"},
+		Tags:        []string{"security"},
+		Security:    &models.SecurityAdvisory{IDs: ids, Summary: id + " summary", Severity: severity},
+	}
+}
+
+func TestOSVAdvisories(t *testing.T) {
+	tests := []struct {
+		name          string
+		entries       []models.ChangelogEntry
+		wantIDs       []string
+		wantAliases   [][]string
+		wantModified  []string
+		wantPublished []string
+	}{
+		{
+			name: "first ID with aliases",
+			entries: []models.ChangelogEntry{
+				{Version: "1.0.0", Date: "2024-01-02", Changes: []models.ChangelogChange{
+					testSecurityChange("fix-a", []string{"CVE-2024-0001", "GHSA-2345-6789-cfgh"}, models.SeverityLow),
+					{ID: "not-security", Title: "Not security", Commits: []string{"c"}, Tags: []string{"fix"}},
+				}},
+			},
+			wantIDs:       []string{"CVE-2024-0001"},
+			wantAliases:   [][]string{{"GHSA-2345-6789-cfgh"}},
+			wantModified:  []string{"2024-01-02T00:00:00Z"},
+			wantPublished: []string{"2024-01-02T00:00:00Z"},
+		},
+		{
+			name: "placeholder ID of an undated entry",
+			entries: []models.ChangelogEntry{
+				{Version: "1.0.0", Changes: []models.ChangelogChange{testSecurityChange("fix-a", nil, models.SeverityLow)}},
+			},
+			wantIDs:       []string{"CHLOG-1.0.0-fix-a"},
+			wantAliases:   [][]string{nil},
+			wantModified:  []string{""},
+			wantPublished: []string{""},
+		},
+		{
+			name: "same ID in a backport",
+			entries: []models.ChangelogEntry{
+				{Version: "2.0.1", Date: "2024-03-01", Changes: []models.ChangelogChange{
+					testSecurityChange("fix-a", []string{"CVE-2024-0001"}, models.SeverityModerate),
+				}},
+				{Version: "1.9.5", Date: "2024-02-01", Changes: []models.ChangelogChange{
+					testSecurityChange("fix-a-backport", []string{"CVE-2024-0001", "GHSA-2345-6789-cfgh"}, models.SeverityHigh),
+				}},
+			},
+			wantIDs:       []string{"CVE-2024-0001"},
+			wantAliases:   [][]string{{"GHSA-2345-6789-cfgh"}},
+			wantModified:  []string{"2024-03-01T00:00:00Z"},
+			wantPublished: []string{"2024-02-01T00:00:00Z"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			advisories := OSVAdvisories(test.entries, "https://github.com/owner/repo")
+			ids := lo.Map(advisories, func(advisory OSVAdvisory, _ int) string { return advisory.ID })
+			if !reflect.DeepEqual(ids, test.wantIDs) {
+				t.Fatalf("OSVAdvisories() IDs = %v, want %v", ids, test.wantIDs)
+			}
+			for i, advisory := range advisories {
+				if !reflect.DeepEqual(advisory.Aliases, test.wantAliases[i]) {
+					t.Errorf("aliases = %v, want %v", advisory.Aliases, test.wantAliases[i])
+				}
+				if advisory.Modified != test.wantModified[i] || advisory.Published != test.wantPublished[i] {
+					t.Errorf("modified, published = %q, %q, want %q, %q", advisory.Modified, advisory.Published, test.wantModified[i], test.wantPublished[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOSVAdvisoriesMerged(t *testing.T) {
+	entries := []models.ChangelogEntry{
+		{Version: "2.0.1", Changes: []models.ChangelogChange{testSecurityChange("fix-a", []string{"CVE-2024-0001"}, models.SeverityModerate)}},
+		{Version: "1.9.5", Changes: []models.ChangelogChange{testSecurityChange("fix-a-backport", []string{"CVE-2024-0001"}, models.SeverityHigh)}},
+	}
+	advisories := OSVAdvisories(entries, "https://github.com/owner/repo")
+	if len(advisories) != 1 {
+		t.Fatalf("OSVAdvisories() returned %d advisories, want 1", len(advisories))
+	}
+
+	advisory := advisories[0]
+	if advisory.Summary != "fix-a summary" {
+		t.Errorf("summary = %q, want the summary of the first change", advisory.Summary)
+	}
+	if advisory.DatabaseSpecific.Severity != models.SeverityHigh {
+		t.Errorf("severity = %q, want the most severe one", advisory.DatabaseSpecific.Severity)
+	}
+	wantMerged := []OSVChange{{Version: "1.9.5", Change: "fix-a-backport"}}
+	if !reflect.DeepEqual(advisory.DatabaseSpecific.Merged, wantMerged) {
+		t.Errorf("merged = %v, want %v", advisory.DatabaseSpecific.Merged, wantMerged)
+	}
+	fixed := lo.Map(advisory.Affected, func(affected OSVAffected, _ int) string { return affected.Ranges[0].Events[1]["fixed"] })
+	if !reflect.DeepEqual(fixed, []string{"fix-a-commit", "fix-a-backport-commit"}) {
+		t.Errorf("fixed commits = %v, want the commits of both changes", fixed)
+	}
+	if len(advisory.References) != 3 {
+		t.Errorf("references = %v, want the advisory and both fix commits", advisory.References)
+	}
+}
@@ -71,3 +71,34 @@ func Select(prompt string, items []string) (string, error) {
 
 	return result, nil
 }
+
+// PromptEdit prompts for a value with the current value pre-filled so it can be edited
+func PromptEdit(prompt, value string) (string, error) {
+	promptUI := promptui.Prompt{
+		Label:     prompt,
+		Default:   value,
+		AllowEdit: true,
+	}
+
+	return promptUI.Run()
+}
+
+// SelectIndex is like Select but returns the index of the selected item
+func SelectIndex(prompt string, items []string) (int, error) {
+	if len(items) == 0 {
+		return -1, fmt.Errorf("no items to select from")
+	}
+
+	promptUI := promptui.Select{
+		Label: prompt,
+		Items: items,
+		Size:  len(items),
+	}
+
+	index, _, err := promptUI.Run()
+	if err != nil {
+		return -1, err
+	}
+
+	return index, nil
+}
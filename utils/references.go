@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+)
+
+const (
+	GitHubReferenceType = "github"
+	JiraReferenceType   = "jira"
+)
+
+// githubReferenceRegex matches GitHub issue and pull request references (e.g. #123 or GH-123) that are not part of a word
+var githubReferenceRegex = regexp.MustCompile(`(?:^|[\s(\[,;:])(?:#|GH-)(\d+)\b`)
+
+// jiraReferenceRegex matches JIRA-style issue keys (e.g. PROJ-456) that are not followed by another dash, so the years
+// of CVE IDs (e.g. CVE-2024-1234) are not matched
+var jiraReferenceRegex = regexp.MustCompile(`\b([A-Z][A-Z0-9]+-\d+)(?:[^\w-]|$)`)
+
+var githubRepositoryRegex = regexp.MustCompile(`^(?:https?://|ssh://git@|git@)github\.com[:/]([^/\s]+)/([^/\s]+?)(?:\.git)?/?$`)
+
+// GitHubRepositoryURL returns the https URL of a GitHub repository from its https or SSH URL, or an empty string if the
+// URL is not a GitHub repository
+func GitHubRepositoryURL(url string) string {
+	match := githubRepositoryRegex.FindStringSubmatch(strings.TrimSpace(url))
+	if match == nil {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/%s", match[1], match[2])
+}
+
+// GetConfigReferencePatterns returns the patterns of the 'reference_patterns' key of the config file, a list of
+// mappings with a type, a pattern (a regular expression) and an optional URL template, followed by the built-in GitHub
+// pattern (linked to the issues of the repository if it is on GitHub) and JIRA-style pattern (not linked). A config
+// pattern with the type of a built-in pattern replaces it, and uses the built-in regular expression if it has no pattern
+// (e.g. to link JIRA issues). The config file must already be loaded.
+func GetConfigReferencePatterns(repositoryURL string) ([]git.ReferencePattern, error) {
+	github := git.ReferencePattern{Type: GitHubReferenceType, Regex: githubReferenceRegex}
+	if url := GitHubRepositoryURL(repositoryURL); url != "" {
+		github.URL = url + "/issues/{id}"
+	}
+	builtIn := []git.ReferencePattern{github, {Type: JiraReferenceType, Regex: jiraReferenceRegex}}
+	if !viper.IsSet("reference_patterns") {
+		return builtIn, nil
+	}
+
+	items, ok := viper.Get("reference_patterns").([]any)
+	if !ok {
+		return nil, fmt.Errorf("Invalid 'reference_patterns' in config file. Expected a list of types, patterns and URLs")
+	}
+	patterns := []git.ReferencePattern{}
+	for i, item := range items {
+		value, _ := item.(map[string]any)
+		referenceType, _ := value["type"].(string)
+		pattern, _ := value["pattern"].(string)
+		url, _ := value["url"].(string)
+		referenceType, pattern = strings.TrimSpace(referenceType), strings.TrimSpace(pattern)
+		existing, isBuiltIn := lo.Find(builtIn, func(existing git.ReferencePattern) bool {
+			return existing.Type == referenceType
+		})
+		if referenceType == "" || (pattern == "" && !isBuiltIn) {
+			return nil, fmt.Errorf("Invalid reference pattern %d in config file. Each reference pattern must have a 'type' and 'pattern' key", i+1)
+		}
+		regex := existing.Regex
+		if pattern != "" {
+			var err error
+			regex, err = regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid pattern '%s' of reference pattern %d in config file: %v", pattern, i+1, err)
+			}
+		}
+		patterns = append(patterns, git.ReferencePattern{Type: referenceType, Regex: regex, URL: strings.TrimSpace(url)})
+	}
+
+	// The built-in patterns come last, so the config patterns take precedence for the references they both match
+	for _, pattern := range builtIn {
+		if !lo.ContainsBy(patterns, func(configured git.ReferencePattern) bool { return configured.Type == pattern.Type }) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns, nil
+}
+
+// ReferenceResolver returns the issue references of commits from git, caching them by commit
+type ReferenceResolver struct {
+	Patterns []git.ReferencePattern
+	commits  map[string][]models.Reference
+}
+
+func NewReferenceResolver(patterns []git.ReferencePattern) *ReferenceResolver {
+	return &ReferenceResolver{Patterns: patterns, commits: map[string][]models.Reference{}}
+}
+
+// CommitReferences returns the references in the message of the commit
+func (r *ReferenceResolver) CommitReferences(commit string) ([]models.Reference, error) {
+	if references, ok := r.commits[commit]; ok {
+		return references, nil
+	}
+	gitReferences, err := git.CommitReferences(commit, r.Patterns)
+	if err != nil {
+		return nil, err
+	}
+	references := lo.Map(gitReferences, func(reference git.Reference, _ int) models.Reference {
+		return models.Reference{Type: reference.Type, ID: reference.Text, URL: reference.URL}
+	})
+	r.commits[commit] = references
+	return references, nil
+}
+
+// AttachChangeReferences sets the references of each change (and variant) of the entry from the messages of its commits.
+// Commits that cannot be found in the repository are skipped.
+func (r *ReferenceResolver) AttachChangeReferences(entry *models.ChangelogEntry) {
+	attach := func(changes []models.ChangelogChange) {
+		for i, change := range changes {
+			references := []models.Reference{}
+			for _, commit := range change.Commits {
+				commitReferences, err := r.CommitReferences(commit)
+				if err != nil {
+					continue
+				}
+				references = append(references, commitReferences...)
+			}
+			changes[i].References = lo.UniqBy(references, func(reference models.Reference) string {
+				return reference.Type + "\x00" + reference.ID
+			})
+			if len(changes[i].References) == 0 {
+				changes[i].References = nil
+			}
+		}
+	}
+
+	attach(entry.Changes)
+	for _, changes := range entry.Variants {
+		attach(changes)
+	}
+}
+
+// HasReferences returns true if any change of the entry has references
+func HasReferences(entry models.ChangelogEntry) bool {
+	return lo.SomeBy(entry.Changes, func(change models.ChangelogChange) bool {
+		return len(change.References) > 0
+	})
+}
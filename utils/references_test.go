@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/spf13/viper"
+)
+
+func TestParseReferences(t *testing.T) {
+	patterns, err := GetConfigReferencePatterns("https://github.com/owner/repo")
+	if err != nil {
+		t.Fatalf("GetConfigReferencePatterns() error: %v", err)
+	}
+	githubReference := func(text, id string) git.Reference {
+		return git.Reference{Type: GitHubReferenceType, Text: text, URL: "https://github.com/owner/repo/issues/" + id}
+	}
+	jiraReference := func(text string) git.Reference {
+		return git.Reference{Type: JiraReferenceType, Text: text}
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		want    []git.Reference
+	}{
+		{"github issue", "Fix the parser (#123)", []git.Reference{githubReference("#123", "123")}},
+		{"GH prefix is not a JIRA key", "Fix the parser, closes GH-45", []git.Reference{githubReference("GH-45", "45")}},
+		{"JIRA keys", "PROJ-1 and PROJ-22: fix the parser", []git.Reference{jiraReference("PROJ-1"), jiraReference("PROJ-22")}},
+		{"JIRA key in brackets", "[AB2-7] Fix the parser", []git.Reference{jiraReference("AB2-7")}},
+		{"CVE IDs are not JIRA keys", "Fix CVE-2024-1234", []git.Reference{}},
+		{"lowercase keys are not JIRA keys", "Bump proj-1", []git.Reference{}},
+		{"duplicates", "Fix #1, see #1 and PROJ-1 (PROJ-1)", []git.Reference{githubReference("#1", "1"), jiraReference("PROJ-1")}},
+		{"trailer links", "Fix the parser\n\nFixes: https://tracker.example.com/issue/9", []git.Reference{
+			{Type: "link", Text: "https://tracker.example.com/issue/9", URL: "https://tracker.example.com/issue/9"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := git.ParseReferences(test.message, patterns); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseReferences(%q) = %v, want %v", test.message, got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetConfigReferencePatterns(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("reference_patterns", []any{
+		map[string]any{"type": "linear", "pattern": `\[(\w+-\d+)\]`, "url": "https://linear.app/example/issue/{id}"},
+		map[string]any{"type": "jira", "url": "https://example.atlassian.net/browse/{id}"},
+	})
+	patterns, err := GetConfigReferencePatterns("")
+	if err != nil {
+		t.Fatalf("GetConfigReferencePatterns() error: %v", err)
+	}
+
+	got := git.ParseReferences("[ENG-12] Fix the parser for PROJ-3 and #4", patterns)
+	want := []git.Reference{
+		{Type: "linear", Text: "ENG-12", URL: "https://linear.app/example/issue/ENG-12"},
+		{Type: JiraReferenceType, Text: "PROJ-3", URL: "https://example.atlassian.net/browse/PROJ-3"},
+		{Type: GitHubReferenceType, Text: "#4"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseReferences() = %v, want %v", got, want)
+	}
+
+	viper.Set("reference_patterns", []any{map[string]any{"type": "linear"}})
+	if _, err := GetConfigReferencePatterns(""); err == nil {
+		t.Error("GetConfigReferencePatterns(): expected an error for a custom type without a pattern")
+	}
+}
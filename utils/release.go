@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/ammar-ahmed22/chlog/models"
+)
+
+// UnreleasedVersion is the version name used for changes that have not been released yet
+const UnreleasedVersion = "Unreleased"
+
+// UnreleasedCommits returns the commits referenced by the changes of the Unreleased entry
+func UnreleasedCommits(entries []models.ChangelogEntry) []string {
+	index := FindChangelogEntry(entries, UnreleasedVersion)
+	if index == -1 {
+		return []string{}
+	}
+
+	return ChangesCommits(entries[index].Changes)
+}
+
+// MergeUnreleasedEntry merges the generated changes into the Unreleased entry, adding the entry at the beginning if there is none.
+// The existing changes are kept as is and the to reference is updated to the end of the generated range.
+func MergeUnreleasedEntry(entries []models.ChangelogEntry, generated models.ChangelogEntry) ([]models.ChangelogEntry, int) {
+	generated.Version = UnreleasedVersion
+	index := FindChangelogEntry(entries, UnreleasedVersion)
+	if index == -1 {
+		return append([]models.ChangelogEntry{generated}, entries...), len(generated.Changes)
+	}
+
+	merged, added := MergeChangelogEntries(entries[index], generated)
+	merged.ToRef = generated.ToRef
+	updated := append([]models.ChangelogEntry{}, entries...)
+	updated[index] = merged
+	return updated, added
+}
+
+// ReleaseUnreleasedEntry renames the Unreleased entry to the version, sets its date and to reference,
+// and starts a new empty Unreleased entry from the released reference.
+func ReleaseUnreleasedEntry(entries []models.ChangelogEntry, version, date, toRef string) ([]models.ChangelogEntry, error) {
+	if version == UnreleasedVersion {
+		return nil, fmt.Errorf("Invalid version '%s'", version)
+	}
+
+	index := FindChangelogEntry(entries, UnreleasedVersion)
+	if index == -1 {
+		return nil, fmt.Errorf("There is no %s entry to release. Use 'chlog generate --unreleased' to create one", UnreleasedVersion)
+	}
+
+	if FindChangelogEntry(entries, version) != -1 {
+		return nil, fmt.Errorf("Version '%s' already exists in the changelog file", version)
+	}
+
+	released := entries[index]
+	released.Version = version
+	released.Date = date
+	released.ToRef = toRef
+
+	updated := append([]models.ChangelogEntry{}, entries...)
+	updated[index] = released
+	unreleased := models.ChangelogEntry{
+		Version: UnreleasedVersion,
+		FromRef: toRef,
+		Changes: []models.ChangelogChange{},
+	}
+	return append([]models.ChangelogEntry{unreleased}, updated...), nil
+}
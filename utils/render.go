@@ -0,0 +1,261 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// MarkdownTagSections maps chlog tags to the Keep a Changelog section names used when rendering Markdown
+var MarkdownTagSections = map[string]string{
+	"feature":       "Added",
+	"improvement":   "Changed",
+	"deprecation":   "Deprecated",
+	"breaking":      "Breaking Changes",
+	"fix":           "Fixed",
+	"security":      "Security",
+	"documentation": "Documentation",
+}
+
+// MarkdownOtherSection is the section of the changes without tags
+const MarkdownOtherSection = "Other"
+
+type RenderOptions struct {
+	Title       string
+	Description string
+	// Audience selects the variant of the changes to render. Internal changes are only rendered for the internal audience.
+	Audience string
+	// Language renders the translations of the changes for the locale (see chlog translate --mode field) when the entry has them
+	Language string
+	// Tags sets the order of the sections
+	Tags []ai.Tag
+	// MinImportance leaves out the changes that are less important (see FilterImportance), all changes are rendered if it is empty
+	MinImportance string
+}
+
+// AudienceChanges returns the changes of the entry written for the audience, or the changes of the entry if there is
+// no variant for the audience. Changes marked as internal are removed unless the audience is internal.
+func AudienceChanges(entry models.ChangelogEntry, audience string) []models.ChangelogChange {
+	changes := entry.Changes
+	if variant, ok := entry.Variants[audience]; ok && audience != entry.Audience {
+		changes = variant
+	}
+	if audience == ai.InternalAudience {
+		return changes
+	}
+	return lo.Filter(changes, func(change models.ChangelogChange, _ int) bool {
+		return !change.Internal
+	})
+}
+
+// TranslateChanges returns the changes with the title, description, impact, upgrade guide and security summary of their
+// translations (matched by ID). The upgrade guide and security information are copied before they are translated.
+func TranslateChanges(changes []models.ChangelogChange, translations []models.ChangelogTranslation) []models.ChangelogChange {
+	if len(translations) == 0 {
+		return changes
+	}
+	return lo.Map(changes, func(change models.ChangelogChange, _ int) models.ChangelogChange {
+		translation, found := lo.Find(translations, func(translation models.ChangelogTranslation) bool {
+			return translation.ID == change.ID
+		})
+		if !found {
+			return change
+		}
+		change.Title = translation.Title
+		change.Description = translation.Description
+		change.Impact = translation.Impact
+		if change.Migration != nil && translation.Migration != nil && translation.Migration.Summary != "" {
+			migration := *change.Migration
+			migration.Summary = translation.Migration.Summary
+			if len(translation.Migration.Actions) == len(migration.Actions) {
+				migration.Actions = translation.Migration.Actions
+			}
+			change.Migration = &migration
+		}
+		if change.Security != nil && translation.SecuritySummary != "" {
+			security := *change.Security
+			security.Summary = translation.SecuritySummary
+			change.Security = &security
+		}
+		return change
+	})
+}
+
+// TranslateSummary returns the summary of the entry from its translation for the locale (see chlog translate --mode
+// field), or the summary of the entry if it has none
+func TranslateSummary(entry models.ChangelogEntry, locale string) string {
+	if translation, ok := entry.SummaryTranslations[locale]; ok && translation.Summary != "" {
+		return translation.Summary
+	}
+	return entry.Summary
+}
+
+// RenderMarkdown renders the changelog entries as a Keep a Changelog style Markdown changelog,
+// with a section per tag (each change is listed under its first tag).
+func RenderMarkdown(entries []models.ChangelogEntry, options RenderOptions) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s\n", lo.Ternary(options.Title != "", options.Title, "Changelog")))
+	if options.Description != "" {
+		builder.WriteString(fmt.Sprintf("\n%s\n", options.Description))
+	}
+
+	for _, entry := range entries {
+		builder.WriteString("\n")
+		if entry.Date != "" {
+			builder.WriteString(fmt.Sprintf("## [%s] - %s\n", entry.Version, entry.Date))
+		} else {
+			builder.WriteString(fmt.Sprintf("## [%s]\n", entry.Version))
+		}
+
+		changes := TranslateChanges(AudienceChanges(entry, options.Audience), entry.Translations[options.Language])
+		changes = FilterImportance(changes, options.MinImportance)
+		if summary := TranslateSummary(entry, options.Language); summary != "" {
+			builder.WriteString(fmt.Sprintf("\n%s\n", strings.TrimSpace(summary)))
+		}
+		// Highlights of changes that are not rendered (e.g. of another audience) are left out
+		highlights := lo.FilterMap(entry.Highlights, func(id string, _ int) (models.ChangelogChange, bool) {
+			return lo.Find(changes, func(change models.ChangelogChange) bool {
+				return change.ID == id
+			})
+		})
+		if len(highlights) > 0 {
+			builder.WriteString("\n### Highlights\n")
+			for _, change := range highlights {
+				builder.WriteString(fmt.Sprintf("- %s\n", change.Title))
+			}
+		}
+
+		for _, tag := range markdownSectionOrder(changes, options.Tags) {
+			builder.WriteString(fmt.Sprintf("\n### %s\n", markdownTagSection(tag)))
+			for _, change := range changes {
+				if markdownChangeTag(change) == tag {
+					builder.WriteString(renderMarkdownChange(change))
+				}
+			}
+		}
+
+		migrations := lo.Filter(changes, func(change models.ChangelogChange, _ int) bool {
+			return change.Migration != nil
+		})
+		if len(migrations) > 0 {
+			builder.WriteString("\n### Upgrade Guide\n")
+			for _, change := range migrations {
+				builder.WriteString(renderMarkdownMigration(change))
+			}
+		}
+
+		if len(entry.Contributors) > 0 {
+			names := lo.Map(entry.Contributors, func(author models.Author, _ int) string {
+				return AuthorDisplayName(author)
+			})
+			builder.WriteString(fmt.Sprintf("\n### Contributors\nThanks to %s for contributing to this release!\n", joinNames(names)))
+		}
+	}
+	return builder.String()
+}
+
+// markdownSectionOrder returns the first tags of the changes, in the order of the tags and then in order of appearance.
+// The changes without tags (an empty tag) come last.
+func markdownSectionOrder(changes []models.ChangelogChange, tags []ai.Tag) []string {
+	used := lo.Uniq(lo.Map(changes, func(change models.ChangelogChange, _ int) string {
+		return markdownChangeTag(change)
+	}))
+	ordered := lo.Filter(ai.TagNames(tags), func(tag string, _ int) bool {
+		return tag != "" && lo.Contains(used, tag)
+	})
+	ordered = append(ordered, lo.Without(used, append(ordered, "")...)...)
+	if lo.Contains(used, "") {
+		ordered = append(ordered, "")
+	}
+	return ordered
+}
+
+// markdownChangeTag returns the first tag of the change, the change is listed in its section. Changes without tags
+// (or with an empty first tag) return an empty tag.
+func markdownChangeTag(change models.ChangelogChange) string {
+	return strings.TrimSpace(lo.FirstOr(change.Tags, ""))
+}
+
+// joinNames joins the names as a sentence (e.g. "a, b and c")
+func joinNames(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+func markdownTagSection(tag string) string {
+	if tag == "" {
+		return MarkdownOtherSection
+	}
+	if section, ok := MarkdownTagSections[tag]; ok {
+		return section
+	}
+	return strings.ToUpper(tag[:1]) + tag[1:]
+}
+
+func renderMarkdownChange(change models.ChangelogChange) string {
+	line := fmt.Sprintf("- **%s**", change.Title)
+	if change.Security != nil && change.Security.Severity != "" {
+		line += fmt.Sprintf(" (%s severity)", change.Security.Severity)
+	}
+	if change.Description != "" {
+		line += ": " + strings.ReplaceAll(strings.TrimSpace(change.Description), "\n", "\n  ")
+	}
+	// References are listed before the commits, linked if they have a URL
+	links := lo.Map(change.References, func(reference models.Reference, _ int) string {
+		return renderMarkdownReference(reference)
+	})
+	// Advisory IDs are listed first, unless they are already references
+	if change.Security != nil {
+		ids := lo.Filter(change.Security.IDs, func(id string, _ int) bool {
+			return !lo.ContainsBy(change.References, func(reference models.Reference) bool { return reference.ID == id })
+		})
+		links = append(lo.Map(ids, func(id string, _ int) string {
+			return renderMarkdownReference(models.Reference{ID: id, URL: advisoryURL(id)})
+		}), links...)
+	}
+	for _, commit := range change.Commits {
+		links = append(links, commit[:min(len(commit), 7)])
+	}
+	if len(links) > 0 {
+		line += fmt.Sprintf(" (%s)", strings.Join(links, ", "))
+	}
+	return line + "\n"
+}
+
+func renderMarkdownReference(reference models.Reference) string {
+	switch {
+	case reference.URL == "":
+		return reference.ID
+	case reference.URL == reference.ID:
+		return fmt.Sprintf("<%s>", reference.URL)
+	default:
+		return fmt.Sprintf("[%s](%s)", reference.ID, reference.URL)
+	}
+}
+
+func renderMarkdownMigration(change models.ChangelogChange) string {
+	migration := change.Migration
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("\n#### %s\n", change.Title))
+	if migration.Summary != "" {
+		builder.WriteString(fmt.Sprintf("%s\n", strings.TrimSpace(migration.Summary)))
+	}
+	if migration.Before != "" {
+		builder.WriteString(fmt.Sprintf("\nBefore:\n```\n%s\n```\n", strings.TrimSpace(migration.Before)))
+	}
+	if migration.After != "" {
+		builder.WriteString(fmt.Sprintf("\nAfter:\n```\n%s\n```\n", strings.TrimSpace(migration.After)))
+	}
+	if len(migration.Actions) > 0 {
+		builder.WriteString("\n")
+		for _, action := range migration.Actions {
+			builder.WriteString(fmt.Sprintf("- %s\n", action))
+		}
+	}
+	return builder.String()
+}
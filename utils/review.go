@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/fatih/color"
+	"github.com/samber/lo"
+)
+
+type ReviewOptions struct {
+	// AIClient and Model are used to rewrite changes with feedback
+	AIClient   ai.AIClient
+	Model      string
+	Tags       []ai.Tag
+	StyleGuide string
+	// Commits are the commits of the range, the commit references of the changes rewritten by the AI are validated
+	// against them
+	Commits []string
+}
+
+const (
+	reviewAccept     = "Accept"
+	reviewReject     = "Reject"
+	reviewEditTitle  = "Edit title"
+	reviewEditDesc   = "Edit description"
+	reviewEditImpact = "Edit impact"
+	reviewEditTags   = "Edit tags"
+	reviewMerge      = "Merge with another change"
+	reviewRewrite    = "Ask the AI to rewrite"
+	reviewAcceptAll  = "Accept this and all remaining changes"
+	reviewAbort      = "Abort"
+)
+
+var reviewActions = []string{reviewAccept, reviewReject, reviewEditTitle, reviewEditDesc, reviewEditImpact, reviewEditTags, reviewMerge, reviewRewrite, reviewAcceptAll, reviewAbort}
+
+// ReviewChangelogEntry interactively reviews each change of a generated entry, where each change can be accepted, rejected,
+// edited, merged with another change or rewritten by the AI with feedback. It returns the entry with the accepted changes.
+func ReviewChangelogEntry(entry models.ChangelogEntry, options ReviewOptions) (models.ChangelogEntry, error) {
+	pending := append([]models.ChangelogChange{}, entry.Changes...)
+	accepted := []models.ChangelogChange{}
+
+	for len(pending) > 0 {
+		change := pending[0]
+		Eprintln("")
+		Eprintf("Change %d of %d\n", len(entry.Changes)-len(pending)+1, len(entry.Changes))
+		printReviewChange(change)
+
+		actions := reviewActions
+		if len(pending) == 1 {
+			actions = lo.Without(actions, reviewMerge, reviewAcceptAll)
+		}
+		index, err := SelectIndex("Review change", actions)
+		if err != nil {
+			return entry, err
+		}
+
+		switch actions[index] {
+		case reviewAccept:
+			accepted = append(accepted, change)
+			pending = pending[1:]
+		case reviewReject:
+			pending = pending[1:]
+		case reviewAcceptAll:
+			accepted = append(accepted, pending...)
+			pending = nil
+		case reviewAbort:
+			return entry, fmt.Errorf("Review aborted")
+		case reviewEditTitle:
+			pending[0].Title, err = PromptEdit("title", change.Title)
+		case reviewEditDesc:
+			pending[0].Description, err = PromptEdit("description", change.Description)
+		case reviewEditImpact:
+			pending[0].Impact, err = PromptEdit("impact", change.Impact)
+		case reviewEditTags:
+			pending[0].Tags, err = promptReviewTags(change.Tags, ai.TagNames(options.Tags))
+		case reviewMerge:
+			pending, err = mergeReviewChange(pending)
+		case reviewRewrite:
+			pending[0], err = rewriteReviewChange(entry, change, options)
+		}
+		if err != nil {
+			return entry, err
+		}
+	}
+
+	entry.Changes = accepted
+	return entry, nil
+}
+
+func printReviewChange(change models.ChangelogChange) {
+	Eprintf("%s %s\n", color.New(color.Bold).Sprint(change.Title), color.New(color.Faint).Sprintf("(%s)", change.ID))
+	Eprintf("  %s %s\n", color.CyanString("description:"), change.Description)
+	Eprintf("  %s %s\n", color.CyanString("impact:"), change.Impact)
+	Eprintf("  %s %s\n", color.CyanString("commits:"), color.YellowString(strings.Join(change.Commits, ", ")))
+	Eprintf("  %s %s\n", color.CyanString("tags:"), color.MagentaString(strings.Join(change.Tags, ", ")))
+}
+
+func promptReviewTags(tags []string, allowedTags []string) ([]string, error) {
+	for {
+		value, err := PromptEdit(fmt.Sprintf("tags (comma-separated, allowed: %s)", strings.Join(allowedTags, ", ")), strings.Join(tags, ", "))
+		if err != nil {
+			return tags, err
+		}
+
+		updated := lo.Compact(lo.Map(strings.Split(value, ","), func(tag string, _ int) string {
+			return strings.TrimSpace(tag)
+		}))
+		invalid := lo.Without(updated, allowedTags...)
+		switch {
+		case len(updated) == 0:
+			Eprintf("%s At least one tag is required\n", color.RedString("\u2717"))
+		case len(invalid) > 0:
+			Eprintf("%s Unknown tags: %s\n", color.RedString("\u2717"), strings.Join(invalid, ", "))
+		default:
+			return lo.Uniq(updated), nil
+		}
+	}
+}
+
+// mergeReviewChange merges another pending change into the first pending change
+func mergeReviewChange(pending []models.ChangelogChange) ([]models.ChangelogChange, error) {
+	others := lo.Map(pending[1:], func(change models.ChangelogChange, _ int) string {
+		return change.Title
+	})
+	index, err := SelectIndex("Merge with", others)
+	if err != nil {
+		return pending, err
+	}
+
+	other := pending[index+1]
+	merged := MergeChangelogChanges(pending[0], other)
+	updated := append([]models.ChangelogChange{merged}, pending[1:index+1]...)
+	return append(updated, pending[index+2:]...), nil
+}
+
+// MergeChangelogChanges merges two changes into one, keeping the ID and title of the first change
+func MergeChangelogChanges(a, b models.ChangelogChange) models.ChangelogChange {
+	joinText := func(a, b string) string {
+		return strings.Join(lo.Compact([]string{strings.TrimSpace(a), strings.TrimSpace(b)}), " ")
+	}
+	merged := a
+	merged.Description = joinText(a.Description, b.Description)
+	merged.Impact = joinText(a.Impact, b.Impact)
+	merged.Commits = lo.Uniq(append(append([]string{}, a.Commits...), b.Commits...))
+	merged.Tags = lo.Uniq(append(append([]string{}, a.Tags...), b.Tags...))
+	return merged
+}
+
+func rewriteReviewChange(entry models.ChangelogEntry, change models.ChangelogChange, options ReviewOptions) (models.ChangelogChange, error) {
+	feedback, err := Prompt("feedback for the AI", "")
+	if err != nil {
+		return change, err
+	}
+
+	entry.Changes = []models.ChangelogChange{change}
+	prompt, err := ai.BuildRegeneratePrompt(ai.RegenerateParams{
+		Entry:      entry,
+		ChangeID:   change.ID,
+		Feedback:   feedback,
+		Tags:       options.Tags,
+		StyleGuide: options.StyleGuide,
+		Language:   entry.Language,
+	})
+	if err != nil {
+		Eprintf("%s Error building prompt: %v\n", color.RedString("\u2717"), err)
+		return change, nil
+	}
+
+	Eprintln("\u2192 AI Rewriting change...")
+	response, err := options.AIClient.GenerateChangelogEntryFromPrompt(options.Model, prompt, ai.TagNames(options.Tags))
+	if err != nil {
+		Eprintf("%s Error rewriting change: %v\n", color.RedString("\u2717"), err)
+		return change, nil
+	}
+	if len(response.Entry.Changes) != 1 {
+		Eprintf("%s Expected 1 change in the AI response, got %d\n", color.RedString("\u2717"), len(response.Entry.Changes))
+		return change, nil
+	}
+
+	validation := ValidateChangelogCommits(&response.Entry, options.Commits, nil)
+	for _, invalid := range validation.Invalid {
+		Eprintf("%s Removed commit '%s' from the rewritten change, it is not in the commit range\n", color.YellowString("!"), invalid.Commit)
+	}
+	rewritten := response.Entry.Changes[0]
+	rewritten.ID = change.ID
+	if len(rewritten.Commits) == 0 {
+		rewritten.Commits = change.Commits
+	}
+	return rewritten, nil
+}
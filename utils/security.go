@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+)
+
+// AdvisoryPatterns match the CVE and GHSA IDs of security advisories in commit messages
+var AdvisoryPatterns = []git.ReferencePattern{
+	{Type: "cve", Regex: regexp.MustCompile(`\bCVE-\d{4}-\d{4,}\b`), URL: "https://nvd.nist.gov/vuln/detail/{id}"},
+	{Type: "ghsa", Regex: regexp.MustCompile(`\bGHSA(?:-[23456789cfghjmpqrvwx]{4}){3}\b`), URL: "https://github.com/advisories/{id}"},
+}
+
+// GetConfigSecurity returns the 'security' key of the config file, security advisories are generated if it is not set.
+// The config file must already be loaded.
+func GetConfigSecurity() bool {
+	return !viper.IsSet("security") || viper.GetBool("security")
+}
+
+// AdvisoryIDs returns the unique CVE and GHSA IDs referenced by the messages of the commits. Commits that cannot be
+// found in the repository are skipped.
+func AdvisoryIDs(resolver *ReferenceResolver, commits []string) []string {
+	ids := []string{}
+	for _, commit := range commits {
+		references, err := resolver.CommitReferences(commit)
+		if err != nil {
+			continue
+		}
+		for _, reference := range references {
+			if lo.ContainsBy(AdvisoryPatterns, func(pattern git.ReferencePattern) bool { return pattern.Type == reference.Type }) {
+				ids = append(ids, reference.ID)
+			}
+		}
+	}
+	return lo.Uniq(ids)
+}
+
+// GenerateSecurityAdvisories generates the advisory information of each security change of the entry that does not have
+// it yet, with a request per change. The changes of the variants get the advisory of the change with the same commits.
+func GenerateSecurityAdvisories(entry *models.ChangelogEntry, options FollowUpOptions) FollowUpResult {
+	resolver := NewReferenceResolver(AdvisoryPatterns)
+	needs := func(change models.ChangelogChange) bool {
+		return ai.NeedsSecurityAdvisory(change) && change.Security == nil
+	}
+	generate := func(change *models.ChangelogChange) (int, int, error) {
+		ids := AdvisoryIDs(resolver, change.Commits)
+		prompt, err := ai.BuildSecurityPrompt(*change, ids, options.Language, options.StyleGuide)
+		if err == nil {
+			var response ai.GenerateSecurityAdvisoryResponse
+			response, err = options.AIClient.GenerateSecurityAdvisory(options.Model, prompt)
+			if err == nil {
+				advisory := response.Advisory
+				advisory.IDs = lo.Ternary(len(ids) > 0, ids, nil)
+				change.Security = &advisory
+				return response.InputTokens, response.OutputTokens, nil
+			}
+		}
+		return 0, 0, fmt.Errorf("Could not generate the security advisory of change '%s': %v", change.Title, err)
+	}
+	share := func(from models.ChangelogChange, to *models.ChangelogChange) {
+		to.Security = from.Security
+	}
+	return generateFollowUps(entry, needs, generate, share)
+}
@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+type semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+}
+
+// parseSemver parses a semantic version (with an optional "v" prefix). Build metadata is ignored. Numeric identifiers
+// with leading zeros (e.g. 1.02.0) are invalid.
+func parseSemver(version string) (semver, bool) {
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "+")
+	core, prerelease, hasPrerelease := strings.Cut(version, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		if !isNumericIdentifier(part) {
+			return semver{}, false
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return semver{}, false
+		}
+		numbers[i] = n
+	}
+
+	result := semver{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}
+	if hasPrerelease {
+		if prerelease == "" {
+			return semver{}, false
+		}
+		result.Prerelease = strings.Split(prerelease, ".")
+		for _, identifier := range result.Prerelease {
+			if !isPrereleaseIdentifier(identifier) {
+				return semver{}, false
+			}
+		}
+	}
+	return result, true
+}
+
+// isNumericIdentifier reports whether the identifier is a number without leading zeros
+func isNumericIdentifier(identifier string) bool {
+	if identifier == "" || (len(identifier) > 1 && identifier[0] == '0') {
+		return false
+	}
+	return strings.Trim(identifier, "0123456789") == ""
+}
+
+// isPrereleaseIdentifier reports whether the identifier is a numeric identifier or a non-empty alphanumeric identifier
+// (letters, digits and hyphens, with at least one non-digit)
+func isPrereleaseIdentifier(identifier string) bool {
+	if identifier == "" || strings.ContainsFunc(identifier, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-')
+	}) {
+		return false
+	}
+	return strings.Trim(identifier, "0123456789") != "" || isNumericIdentifier(identifier)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareSemver compares two versions following the semver precedence rules
+// (e.g. 1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-beta < 1.0.0).
+func compareSemver(a, b semver) int {
+	if c := compareInts(a.Major, b.Major); c != 0 {
+		return c
+	}
+	if c := compareInts(a.Minor, b.Minor); c != 0 {
+		return c
+	}
+	if c := compareInts(a.Patch, b.Patch); c != 0 {
+		return c
+	}
+
+	// A version without pre-release identifiers has higher precedence
+	if len(a.Prerelease) == 0 || len(b.Prerelease) == 0 {
+		return -compareInts(len(a.Prerelease), len(b.Prerelease))
+	}
+
+	for i := 0; i < len(a.Prerelease) && i < len(b.Prerelease); i++ {
+		aNum, aErr := strconv.Atoi(a.Prerelease[i])
+		bNum, bErr := strconv.Atoi(b.Prerelease[i])
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInts(aNum, bNum)
+		case aErr == nil:
+			// Numeric identifiers have lower precedence than alphanumeric ones
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(a.Prerelease[i], b.Prerelease[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(a.Prerelease), len(b.Prerelease))
+}
+
+// CompareVersions compares two changelog versions. Semantic versions are compared by precedence and
+// date-based versions (YYYY-MM-DD, the default version of chlog generate) by date.
+// Returns false if the versions cannot be compared.
+func CompareVersions(a, b string) (int, bool) {
+	aSemver, aOk := parseSemver(a)
+	bSemver, bOk := parseSemver(b)
+	if aOk && bOk {
+		return compareSemver(aSemver, bSemver), true
+	}
+
+	aDate, aErr := time.Parse("2006-01-02", a)
+	bDate, bErr := time.Parse("2006-01-02", b)
+	if aErr == nil && bErr == nil {
+		return aDate.Compare(bDate), true
+	}
+	return 0, false
+}
+
+// versionKind is the kind of the version of an entry. Entries of a higher kind are newer than any entry of a lower kind.
+type versionKind int
+
+const (
+	// otherVersion is a version that is neither a semantic nor a date-based version (e.g. "nightly")
+	otherVersion versionKind = iota
+	dateVersion
+	semverVersion
+	unreleasedVersion
+)
+
+func entryVersionKind(entry models.ChangelogEntry) versionKind {
+	if entry.Version == UnreleasedVersion {
+		return unreleasedVersion
+	}
+	if _, ok := parseSemver(entry.Version); ok {
+		return semverVersion
+	}
+	if _, err := time.Parse("2006-01-02", entry.Version); err == nil {
+		return dateVersion
+	}
+	return otherVersion
+}
+
+// CompareChangelogEntries compares two entries by version. The Unreleased entry is newer than any other entry, then
+// semantic versions (compared by precedence) are newer than date-based versions (YYYY-MM-DD, the default version of
+// chlog generate), which are newer than any other versions. Entries with other versions are compared by date, and
+// entries without a valid date are older than the ones with one.
+func CompareChangelogEntries(a, b models.ChangelogEntry) int {
+	aKind, bKind := entryVersionKind(a), entryVersionKind(b)
+	if aKind != bKind {
+		return compareInts(int(aKind), int(bKind))
+	}
+
+	switch aKind {
+	case semverVersion, dateVersion:
+		c, _ := CompareVersions(a.Version, b.Version)
+		return c
+	case otherVersion:
+		aDate, aErr := time.Parse("2006-01-02", a.Date)
+		bDate, bErr := time.Parse("2006-01-02", b.Date)
+		if aErr != nil || bErr != nil {
+			return compareInts(lo.Ternary(aErr == nil, 1, 0), lo.Ternary(bErr == nil, 1, 0))
+		}
+		return aDate.Compare(bDate)
+	}
+	return 0
+}
+
+// SortChangelogEntries sorts the entries from the most recent to the oldest version.
+// Entries that compare as equal keep their relative order.
+func SortChangelogEntries(entries []models.ChangelogEntry) []models.ChangelogEntry {
+	sorted := append([]models.ChangelogEntry{}, entries...)
+	slices.SortStableFunc(sorted, func(a, b models.ChangelogEntry) int {
+		return -CompareChangelogEntries(a, b)
+	})
+	return sorted
+}
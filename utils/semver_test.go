@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		version string
+		valid   bool
+	}{
+		{"1.2.3", true},
+		{"v1.2.3", true},
+		{"0.0.0", true},
+		{"1.2.3-alpha.1", true},
+		{"1.2.3-0.alpha-1", true},
+		{"1.2.3+build.5", true},
+		{"1.2", false},
+		{"1.2.3.4", false},
+		{"01.2.3", false},
+		{"1.02.3", false},
+		{"1.2.03", false},
+		{"1.2.3-", false},
+		{"1.2.3-alpha..1", false},
+		{"1.2.3-01", false},
+		{"1.2.3-alpha_1", false},
+		{"+1.2.3", false},
+		{"2024-01-01", false},
+		{"nightly", false},
+	}
+	for _, test := range tests {
+		if _, ok := parseSemver(test.version); ok != test.valid {
+			t.Errorf("parseSemver(%q) valid = %v, want %v", test.version, ok, test.valid)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		want       int
+		comparable bool
+	}{
+		{"1.0.0", "1.0.0", 0, true},
+		{"1.0.0", "2.0.0", -1, true},
+		{"1.10.0", "1.9.0", 1, true},
+		{"v1.0.1", "1.0.0", 1, true},
+		{"1.0.0-alpha", "1.0.0", -1, true},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1, true},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1, true},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1, true},
+		{"1.0.0-rc.1", "1.0.0-beta", 1, true},
+		{"1.0.0+build.1", "1.0.0+build.2", 0, true},
+		{"2024-01-02", "2024-01-01", 1, true},
+		{"1.0.0", "2024-01-01", 0, false},
+		{"nightly", "1.0.0", 0, false},
+	}
+	for _, test := range tests {
+		got, ok := CompareVersions(test.a, test.b)
+		if got != test.want || ok != test.comparable {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v, want %d, %v", test.a, test.b, got, ok, test.want, test.comparable)
+		}
+	}
+}
+
+func TestCompareChangelogEntries(t *testing.T) {
+	entry := func(version, date string) models.ChangelogEntry {
+		return models.ChangelogEntry{Version: version, Date: date}
+	}
+	tests := []struct {
+		name string
+		a, b models.ChangelogEntry
+		want int
+	}{
+		{"unreleased is newest", entry(UnreleasedVersion, ""), entry("9.0.0", "2030-01-01"), 1},
+		{"semver precedence", entry("1.0.0", "2030-01-01"), entry("1.1.0", "2020-01-01"), -1},
+		{"semver is newer than dates", entry("1.0.0", "2020-01-01"), entry("2024-01-01", "2024-01-01"), 1},
+		{"date versions", entry("2024-01-02", ""), entry("2024-01-01", ""), 1},
+		{"date is newer than other", entry("2024-01-01", ""), entry("nightly", "2030-01-01"), 1},
+		{"other by date", entry("nightly", "2024-01-02"), entry("beta", "2024-01-01"), 1},
+		{"other without date is oldest", entry("nightly", ""), entry("beta", "2024-01-01"), -1},
+		{"other without dates", entry("nightly", ""), entry("beta", "soon"), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CompareChangelogEntries(test.a, test.b); got != test.want {
+				t.Errorf("CompareChangelogEntries(a, b) = %d, want %d", got, test.want)
+			}
+			if got := CompareChangelogEntries(test.b, test.a); got != -test.want {
+				t.Errorf("CompareChangelogEntries(b, a) = %d, want %d", got, -test.want)
+			}
+		})
+	}
+}
+
+// TestSortChangelogEntriesMixedVersions checks that mixed version kinds are sorted the same way regardless of the
+// initial order, which requires CompareChangelogEntries to be a strict weak ordering
+func TestSortChangelogEntriesMixedVersions(t *testing.T) {
+	entries := []models.ChangelogEntry{
+		{Version: "2024-03-01", Date: "2024-03-01"},
+		{Version: "1.0.0", Date: "2024-04-01"},
+		{Version: "nightly", Date: "2024-05-01"},
+		{Version: UnreleasedVersion},
+		{Version: "1.1.0-rc.1", Date: "2024-02-01"},
+		{Version: "2024-01-01", Date: "2024-01-01"},
+		{Version: "1.1.0", Date: "2024-01-15"},
+	}
+	want := []string{UnreleasedVersion, "1.1.0", "1.1.0-rc.1", "1.0.0", "2024-03-01", "2024-01-01", "nightly"}
+
+	for i := range entries {
+		rotated := append(append([]models.ChangelogEntry{}, entries[i:]...), entries[:i]...)
+		slices.Reverse(rotated)
+		got := lo.Map(SortChangelogEntries(rotated), func(entry models.ChangelogEntry, _ int) string { return entry.Version })
+		if !slices.Equal(got, want) {
+			t.Errorf("SortChangelogEntries() (rotation %d) = %v, want %v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+)
+
+// GetConfigHighlights returns the 'highlights' key of the config file, or the default number of highlights if it is not
+// set. The config file must already be loaded.
+func GetConfigHighlights() int {
+	if !viper.IsSet("highlights") {
+		return ai.DefaultHighlights
+	}
+	return viper.GetInt("highlights")
+}
+
+type SummaryOptions struct {
+	AIClient   ai.AIClient
+	Model      string
+	StyleGuide string
+	// Highlights is the maximum number of highlights
+	Highlights int
+}
+
+type SummaryResult struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// GenerateSummary sets the summary and highlights of the entry. Changes marked as internal are left out unless the
+// entry was written for the internal audience, and highlights that are not IDs of the other changes are removed.
+func GenerateSummary(entry *models.ChangelogEntry, options SummaryOptions) (SummaryResult, error) {
+	summarized := *entry
+	summarized.Changes = AudienceChanges(*entry, entry.Audience)
+	prompt, err := ai.BuildSummaryPrompt(summarized, options.Highlights, entry.Language, options.StyleGuide)
+	if err != nil {
+		return SummaryResult{}, err
+	}
+	response, err := options.AIClient.GenerateSummary(options.Model, prompt)
+	if err != nil {
+		return SummaryResult{}, err
+	}
+
+	highlights := lo.Filter(lo.Uniq(response.Summary.Highlights), func(id string, _ int) bool {
+		return FindChangelogChange(summarized, id) != -1
+	})
+	entry.Summary = response.Summary.Summary
+	entry.Highlights = highlights[:min(len(highlights), options.Highlights)]
+	if len(entry.Highlights) == 0 {
+		entry.Highlights = nil
+	}
+	return SummaryResult{InputTokens: response.InputTokens, OutputTokens: response.OutputTokens}, nil
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/git"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ParsePromptTemplate returns the contents of the prompt template from the '--prompt-template' flag or the
+// 'prompt_template' key of the config file (resolved relative to the config file).
+// An empty string is returned (so the built-in prompt is used) if no template is set or the template is invalid.
+func ParsePromptTemplate(cmd *cobra.Command, configPath string) (string, error) {
+	path, err := cmd.Flags().GetString("prompt-template")
+	if err != nil {
+		return "", err
+	}
+	if path == "" && viper.IsSet("prompt_template") {
+		path = viper.GetString("prompt_template")
+		if path != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(configPath), path)
+		}
+	}
+	if path == "" {
+		return "", nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Error reading prompt template '%s': %v", path, err)
+	}
+
+	err = ai.ValidatePromptTemplate(string(contents))
+	if err != nil {
+		Eprintf("%s Invalid prompt template '%s': %v. Using the built-in prompt\n", color.YellowString("!"), path, err)
+		return "", nil
+	}
+	return string(contents), nil
+}
+
+// GetConfigStyleGuide returns the 'style_guide' of the config file. The config file must already be loaded.
+func GetConfigStyleGuide() string {
+	return viper.GetString("style_guide")
+}
+
+// ChangelogRepositoryInfo returns the title, description and repository of the changelog file for prompt templates.
+// The URL falls back to the origin remote of the Git repository.
+func ChangelogRepositoryInfo(file *ParsedChangelogFile) ai.RepositoryInfo {
+	var info ai.RepositoryInfo
+	if file != nil {
+		contents, err := os.ReadFile(file.Path)
+		if err == nil {
+			doc, err := decodeChangelogDocument(ChangelogFormatFromPath(file.Path), contents)
+			if err == nil && doc.Fields != nil {
+				info.Title, _ = doc.Fields["title"].(string)
+				info.Description, _ = doc.Fields["description"].(string)
+				info.URL, _ = doc.Fields["repository"].(string)
+			}
+		}
+	}
+	if info.URL == "" {
+		info.URL, _ = git.RemoteURL()
+	}
+	return info
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ammar-ahmed22/chlog/ai"
+	"github.com/ammar-ahmed22/chlog/models"
+	"github.com/samber/lo"
+)
+
+// TranslationMode is where chlog translate stores the translations
+type TranslationMode string
+
+const (
+	// TranslationFiles writes a changelog file per locale next to the changelog file (e.g. changelog.de.json)
+	TranslationFiles TranslationMode = "files"
+	// TranslationField stores the translations in the 'translations' field of each entry of the changelog file
+	TranslationField TranslationMode = "field"
+)
+
+// ParseTranslationMode parses the translation mode, an empty value defaults to TranslationFiles
+func ParseTranslationMode(value string) (TranslationMode, error) {
+	switch TranslationMode(value) {
+	case "":
+		return TranslationFiles, nil
+	case TranslationFiles, TranslationField:
+		return TranslationMode(value), nil
+	default:
+		return "", fmt.Errorf("Invalid mode '%s'. Valid modes are: %s, %s", value, TranslationFiles, TranslationField)
+	}
+}
+
+// TranslatedFilePath returns the path of the changelog file for the locale, e.g. changelog.de.json for changelog.json
+func TranslatedFilePath(path, locale string) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(path, ext), locale, ext)
+}
+
+// sourceHash returns a short hash of the JSON of the source text of a translation
+func sourceHash(source any) string {
+	data, _ := json.Marshal(source)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// EntrySourceHash returns the hash of the text of the entry that is translated (see ai.TranslationSource)
+func EntrySourceHash(entry models.ChangelogEntry) string {
+	return sourceHash(ai.TranslationSource(entry))
+}
+
+// changeSourceHashes returns the hashes of the text of each change of the entry that is translated, by change ID
+func changeSourceHashes(entry models.ChangelogEntry) map[string]string {
+	return lo.SliceToMap(ai.TranslationSource(entry).Changes, func(change models.ChangelogTranslation) (string, string) {
+		return change.ID, sourceHash(change)
+	})
+}
+
+// IsEntryTranslated reports whether the translated entry was translated from the current text of the entry
+func IsEntryTranslated(entry models.ChangelogEntry, translated *models.ChangelogEntry) bool {
+	return translated != nil && translated.SourceHash == EntrySourceHash(entry)
+}
+
+// IsEntryTranslatedField reports whether the 'translations' and 'summary_translations' fields of the entry have
+// translations of the current text of all the changes and the summary of the entry for the locale
+func IsEntryTranslatedField(entry models.ChangelogEntry, locale string) bool {
+	if entry.Summary != "" && entry.SummaryTranslations[locale].SourceHash != sourceHash(entry.Summary) {
+		return false
+	}
+	translations := entry.Translations[locale]
+	for id, hash := range changeSourceHashes(entry) {
+		if !lo.ContainsBy(translations, func(translation models.ChangelogTranslation) bool {
+			return translation.ID == id && translation.SourceHash == hash
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// TranslatedChangelogEntry returns the entry with the translated text (in the same order as the changes) and the
+// SourceHash of the entry. The other fields are kept, except for the variants and translations, which are not
+// translated.
+func TranslatedChangelogEntry(entry models.ChangelogEntry, translation models.ChangelogEntryTranslation, locale string) (models.ChangelogEntry, error) {
+	if len(translation.Changes) != len(entry.Changes) {
+		return entry, fmt.Errorf("expected %d translated changes, got %d", len(entry.Changes), len(translation.Changes))
+	}
+
+	translated := entry
+	translated.Language = locale
+	translated.Variants = nil
+	translated.Translations = nil
+	translated.SummaryTranslations = nil
+	translated.SourceHash = EntrySourceHash(entry)
+	if entry.Summary != "" {
+		translated.Summary = translation.Summary
+	}
+	// The changes are translated in the same order, so the IDs are kept even if the LLM changed them
+	changes := make([]models.ChangelogTranslation, len(translation.Changes))
+	for i, change := range translation.Changes {
+		change.ID = entry.Changes[i].ID
+		changes[i] = change
+	}
+	translated.Changes = TranslateChanges(entry.Changes, changes)
+	return translated, nil
+}
+
+// ChangelogTranslations returns the translations of the changes of an entry translated with TranslatedChangelogEntry,
+// for the 'translations' field of the entry
+func ChangelogTranslations(entry models.ChangelogEntry, translated models.ChangelogEntry) []models.ChangelogTranslation {
+	hashes := changeSourceHashes(entry)
+	return lo.Map(ai.TranslationSource(translated).Changes, func(translation models.ChangelogTranslation, _ int) models.ChangelogTranslation {
+		translation.SourceHash = hashes[translation.ID]
+		return translation
+	})
+}
+
+// SummaryTranslation returns the translation of the summary of an entry translated with TranslatedChangelogEntry, for
+// the 'summary_translations' field of the entry
+func SummaryTranslation(entry models.ChangelogEntry, translated models.ChangelogEntry) models.SummaryTranslation {
+	return models.SummaryTranslation{Summary: translated.Summary, SourceHash: sourceHash(entry.Summary)}
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ammar-ahmed22/chlog/models"
+)
+
+func testTranslateEntry() models.ChangelogEntry {
+	return models.ChangelogEntry{
+		Version: "2.0.0",
+		Summary: "A release",
+		Changes: []models.ChangelogChange{
+			{
+				ID: "rename-flag", Title: "Rename flag", Description: "D", Impact: "I", Commits: []string{"a"}, Tags: []string{"breaking"},
+				Migration: &models.Migration{Summary: "Renamed", Before: "--out", After: "--output", Actions: []string{"Use --output"}},
+			},
+			{
+				ID: "fix-traversal", Title: "Fix traversal", Description: "D", Impact: "I", Commits: []string{"b"}, Tags: []string{"security"},
+				Security: &models.SecurityAdvisory{IDs: []string{"CVE-2024-0001"}, Summary: "Path traversal", Severity: models.SeverityHigh},
+			},
+		},
+	}
+}
+
+func testTranslation() models.ChangelogEntryTranslation {
+	return models.ChangelogEntryTranslation{
+		Summary: "Eine Version",
+		Changes: []models.ChangelogTranslation{
+			{ID: "rename-flag", Title: "Flag umbenannt", Description: "B", Impact: "A", Migration: &models.MigrationTranslation{Summary: "Umbenannt", Actions: []string{"Nutze --output"}}},
+			// The LLM changed the ID and returned an empty upgrade guide for a change without one
+			{ID: "other", Title: "Traversal behoben", Description: "B", Impact: "A", Migration: &models.MigrationTranslation{}, SecuritySummary: "Pfad-Traversal"},
+		},
+	}
+}
+
+func TestTranslatedChangelogEntry(t *testing.T) {
+	entry := testTranslateEntry()
+	translated, err := TranslatedChangelogEntry(entry, testTranslation(), "de")
+	if err != nil {
+		t.Fatalf("TranslatedChangelogEntry() error: %v", err)
+	}
+
+	if translated.Summary != "Eine Version" || translated.Language != "de" {
+		t.Errorf("summary, language = %q, %q", translated.Summary, translated.Language)
+	}
+	rename, traversal := translated.Changes[0], translated.Changes[1]
+	if rename.Migration.Summary != "Umbenannt" || rename.Migration.Actions[0] != "Nutze --output" || rename.Migration.Before != "--out" {
+		t.Errorf("migration = %+v, want the translated summary and actions with the original snippets", rename.Migration)
+	}
+	if traversal.ID != "fix-traversal" || traversal.Title != "Traversal behoben" || traversal.Migration != nil {
+		t.Errorf("change = %+v, want the translation matched by position without an upgrade guide", traversal)
+	}
+	if traversal.Security.Summary != "Pfad-Traversal" || entry.Changes[1].Security.Summary != "Path traversal" {
+		t.Errorf("security summary = %q, original = %q", traversal.Security.Summary, entry.Changes[1].Security.Summary)
+	}
+	if !IsEntryTranslated(entry, &translated) {
+		t.Error("IsEntryTranslated() = false for a fresh translation")
+	}
+
+	entry.Changes[1].Security = &models.SecurityAdvisory{Summary: "Edited"}
+	if IsEntryTranslated(entry, &translated) {
+		t.Error("IsEntryTranslated() = true after the security summary was edited")
+	}
+}
+
+func TestIsEntryTranslatedField(t *testing.T) {
+	translatedField := func() models.ChangelogEntry {
+		entry := testTranslateEntry()
+		translated, err := TranslatedChangelogEntry(entry, testTranslation(), "de")
+		if err != nil {
+			t.Fatalf("TranslatedChangelogEntry() error: %v", err)
+		}
+		entry.Translations = map[string][]models.ChangelogTranslation{"de": ChangelogTranslations(entry, translated)}
+		entry.SummaryTranslations = map[string]models.SummaryTranslation{"de": SummaryTranslation(entry, translated)}
+		return entry
+	}
+
+	tests := []struct {
+		name   string
+		edit   func(entry *models.ChangelogEntry)
+		locale string
+		want   bool
+	}{
+		{"fresh translation", func(entry *models.ChangelogEntry) {}, "de", true},
+		{"other locale", func(entry *models.ChangelogEntry) {}, "fr", false},
+		{"edited title", func(entry *models.ChangelogEntry) { entry.Changes[0].Title = "Edited" }, "de", false},
+		{"edited upgrade guide", func(entry *models.ChangelogEntry) { entry.Changes[0].Migration.Actions = []string{"Edited"} }, "de", false},
+		{"edited summary", func(entry *models.ChangelogEntry) { entry.Summary = "Edited" }, "de", false},
+		{"new change", func(entry *models.ChangelogEntry) {
+			entry.Changes = append(entry.Changes, models.ChangelogChange{ID: "new", Title: "New"})
+		}, "de", false},
+		{"edited commits", func(entry *models.ChangelogEntry) { entry.Changes[0].Commits = []string{"c"} }, "de", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry := translatedField()
+			test.edit(&entry)
+			if got := IsEntryTranslatedField(entry, test.locale); got != test.want {
+				t.Errorf("IsEntryTranslatedField() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,7 +11,6 @@ import (
 
 	"github.com/ammar-ahmed22/chlog/models"
 	"github.com/samber/lo"
-	"github.com/tidwall/sjson"
 )
 
 func Eprintln(args ...any) {
@@ -35,6 +36,10 @@ func TruncatedKebabCase(s string, maxLen int) string {
 		}
 		result.WriteString(word)
 	}
+	// The first word is longer than maxLen
+	if result.Len() == 0 && len(kebab) > 0 {
+		return strings.TrimSuffix(kebab[:min(len(kebab), maxLen)], "-")
+	}
 	return result.String()
 }
 
@@ -56,23 +61,95 @@ func hasEntries(contents []byte) (exists bool, entries []models.ChangelogEntry,
 	return true, entries, nil
 }
 
-func ParseAndValidateChangelogFile(path string) ([]models.ChangelogEntry, bool, error) {
+// ParsedChangelogFile is a changelog file read by ParseAndValidateChangelogFile
+type ParsedChangelogFile struct {
+	Path       string
+	Entries    []models.ChangelogEntry
+	EntriesKey bool
+	// Checksum of the file contents when it was read, used to detect changes made to the file before it is written
+	Checksum string
+	// unlock releases the lock taken by LockChangelogFile, nil if the file is not locked
+	unlock func()
+}
+
+// LockChangelogFile locks the changelog file and reads it like ParseAndValidateChangelogFile. The lock is held until
+// Unlock is called, so other chlog commands that update the file wait until the changes are written instead of failing.
+func LockChangelogFile(path string) (*ParsedChangelogFile, error) {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := ParseAndValidateChangelogFile(path)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	file.unlock = unlock
+	return file, nil
+}
+
+// Unlock releases the lock taken by LockChangelogFile. It does nothing if the file is nil or not locked.
+func (file *ParsedChangelogFile) Unlock() {
+	if file != nil && file.unlock != nil {
+		file.unlock()
+		file.unlock = nil
+	}
+}
+
+type WriteChangelogOptions struct {
+	// Backup keeps a copy of the previous contents of the file at <path>.bak
+	Backup bool
+}
+
+func ParseAndValidateChangelogFile(path string) (*ParsedChangelogFile, error) {
 	_, err := os.Stat(path)
 	if err != nil {
 		// File does not exist, create one
 		if os.IsNotExist(err) {
 			// Create an empty changelog file
-			err := os.WriteFile(path, []byte("[]"), 0644)
+			err := os.WriteFile(path, emptyChangelogContents(ChangelogFormatFromPath(path)), 0644)
 			if err != nil {
-				return nil, false, fmt.Errorf("Error creating changelog file '%s': %v", path, err)
+				return nil, fmt.Errorf("Error creating changelog file '%s': %v", path, err)
 			}
 		} else {
-			return nil, false, fmt.Errorf("Error checking changelog file '%s': %v", path, err)
+			return nil, fmt.Errorf("Error checking changelog file '%s': %v", path, err)
 		}
 	}
+	return ReadChangelogFile(path)
+}
+
+// ReadChangelogFile reads a changelog file like ParseAndValidateChangelogFile, but a file that does not exist is not
+// created (e.g. for a dry run), it has no entries instead
+func ReadChangelogFile(path string) (*ParsedChangelogFile, error) {
 	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &ParsedChangelogFile{Path: path, Entries: []models.ChangelogEntry{}}, nil
+	}
 	if err != nil {
-		return nil, false, fmt.Errorf("Error reading file '%s': %v", path, err)
+		return nil, fmt.Errorf("Error reading file '%s': %v", path, err)
+	}
+
+	entries, entriesKey, err := parseChangelogContents(path, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParsedChangelogFile{
+		Path:       path,
+		Entries:    entries,
+		EntriesKey: entriesKey,
+		Checksum:   contentsChecksum(contents),
+	}, nil
+}
+
+func parseChangelogContents(path string, contents []byte) ([]models.ChangelogEntry, bool, error) {
+	format := ChangelogFormatFromPath(path)
+	if format != JSONFormat {
+		doc, err := decodeChangelogDocument(format, contents)
+		if err != nil {
+			return nil, false, fmt.Errorf("Changelog file '%s' is not valid %s: %v. See https://github.com/ammar-ahmed22/chlog#-json-format for the expected format", path, strings.ToUpper(string(format)), err)
+		}
+		return doc.Entries, doc.hasEntriesKey(), nil
 	}
 
 	if len(contents) == 0 {
@@ -80,7 +157,7 @@ func ParseAndValidateChangelogFile(path string) ([]models.ChangelogEntry, bool,
 	}
 
 	var changelogEntries []models.ChangelogEntry
-	err = json.Unmarshal(contents, &changelogEntries)
+	err := json.Unmarshal(contents, &changelogEntries)
 	if err != nil {
 		// Check if the file has JSON with "entries" key
 		exists, entries, err := hasEntries(contents)
@@ -93,38 +170,83 @@ func ParseAndValidateChangelogFile(path string) ([]models.ChangelogEntry, bool,
 	return changelogEntries, false, nil
 }
 
-func WriteChangelogFile(path string, entriesKey bool, changelog []models.ChangelogEntry) error {
-	newEntries, err := json.MarshalIndent(changelog, "", "  ")
-	if err != nil {
-		return fmt.Errorf("Error marshalling changelog to JSON: %v", err)
-	}
-	if !entriesKey {
-		err = os.WriteFile(path, newEntries, 0644)
+func contentsChecksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteChangelogFile writes the changelog entries to a changelog file read by LockChangelogFile or
+// ParseAndValidateChangelogFile. A file that is not locked yet is locked while it is written, the write fails if the
+// file was changed on disk since it was read, and the new contents are written to a temporary file that replaces the
+// changelog file, so it is never left half written.
+func WriteChangelogFile(file *ParsedChangelogFile, changelog []models.ChangelogEntry, options WriteChangelogOptions) error {
+	if file.unlock == nil {
+		unlock, err := lockFile(file.Path)
 		if err != nil {
-			return fmt.Errorf("Error writing changelog file '%s': %v", path, err)
+			return err
 		}
-		return nil
+		defer unlock()
 	}
-	// Write to the "entries" key in the JSON file
-	fileData, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("Error reading file '%s': %v", path, err)
+
+	fileData, err := os.ReadFile(file.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Error reading file '%s': %v", file.Path, err)
+	}
+
+	if file.Checksum != "" && contentsChecksum(fileData) != file.Checksum {
+		return fmt.Errorf("Changelog file '%s' was changed on disk since it was read (e.g. by another chlog run). Run the command again to include the latest changes", file.Path)
 	}
 
-	updatedContents, err := sjson.SetBytes(fileData, "entries", json.RawMessage(newEntries))
+	contents, err := encodeChangelogContents(file.Path, fileData, file.EntriesKey, changelog)
 	if err != nil {
-		return fmt.Errorf("Error marshalling changelog to JSON: %v", err)
+		return err
 	}
 
-	var pretty bytes.Buffer
-	if err := json.Indent(&pretty, updatedContents, "", "  "); err != nil {
-		return fmt.Errorf("Error formatting JSON: %v", err)
+	if options.Backup && fileData != nil {
+		err = writeFileAtomic(file.Path+".bak", fileData)
+		if err != nil {
+			return fmt.Errorf("Error writing backup file '%s': %v", file.Path+".bak", err)
+		}
 	}
 
-	err = os.WriteFile(path, pretty.Bytes(), 0644)
+	err = writeFileAtomic(file.Path, contents)
 	if err != nil {
-		return fmt.Errorf("Error writing changelog file '%s': %v", path, err)
+		return fmt.Errorf("Error writing changelog file '%s': %v", file.Path, err)
 	}
 
+	file.Entries = changelog
+	file.Checksum = contentsChecksum(contents)
 	return nil
 }
+
+// encodeChangelogContents returns the updated contents of a changelog file with the given entries.
+// JSON files are updated with minimal edits (see spliceJSONEntries) so the formatting, key order and any extra fields
+// of existing entries are kept. YAML and TOML files keep any other top-level keys of the existing file.
+func encodeChangelogContents(path string, fileData []byte, entriesKey bool, changelog []models.ChangelogEntry) ([]byte, error) {
+	format := ChangelogFormatFromPath(path)
+	var contents []byte
+	var err error
+	switch {
+	case format == JSONFormat && len(bytes.TrimSpace(fileData)) == 0:
+		contents, err = json.MarshalIndent(changelog, "", "  ")
+	case format == JSONFormat:
+		contents, err = spliceJSONEntries(fileData, entriesKey, changelog)
+	case format == YAMLFormat && entriesKey:
+		// Edit the YAML node tree directly to keep comments and key order
+		contents, err = replaceYAMLEntries(fileData, changelog)
+	default:
+		doc := &changelogDocument{Entries: changelog}
+		if entriesKey {
+			doc, err = decodeChangelogDocument(format, fileData)
+			if err != nil {
+				return nil, fmt.Errorf("Error parsing changelog file '%s': %v", path, err)
+			}
+			doc.Entries = changelog
+		}
+		contents, err = encodeChangelogDocument(format, doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error marshalling changelog to %s: %v", strings.ToUpper(string(format)), err)
+	}
+	return contents, nil
+}